@@ -0,0 +1,327 @@
+package poker
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"gopkg.in/yaml.v3"
+)
+
+// TimestampHeader is the header a signed request carries the Unix time it was
+// signed at in, see SignRequest.
+const TimestampHeader = "X-Poker-Timestamp"
+
+// NonceHeader is the header a signed request carries a value in that is
+// never signed with twice, see SignRequest.
+const NonceHeader = "X-Poker-Nonce"
+
+// MaxSignatureAge is how far a signed request's timestamp can be from the
+// server's clock, either way, before it is rejected as a replay.
+const MaxSignatureAge = 5 * time.Minute
+
+// maxSignedBody is the most of a request body that is read to check its
+// signature.
+const maxSignedBody = 1 << 20
+
+var (
+    ErrNoCredentials     = errors.New("no API token or signature")
+    ErrBadToken          = errors.New("unknown API token")
+    ErrBadSignature      = errors.New("bad signature")
+    ErrStaleSignature    = errors.New("signature timestamp is too old or too new")
+    ErrReplayedSignature = errors.New("signature has already been used")
+    ErrBadAuthConfig     = errors.New("bad auth config")
+)
+
+// AuthConfig is who may change the league and control games. Tokens are the
+// API tokens sent as "Authorization: Bearer <token>", by the name of who
+// uses them. HMACKeys are the secrets requests are signed with, by key ID,
+// see SignRequest. In YAML, e.g.
+//
+//  tokens:
+//    front-desk: 6c1e0a7f9b
+//  hmacKeys:
+//    scoreboard: 93d2b6f4ae
+type AuthConfig struct {
+    Tokens   map[string]string `json:"tokens" yaml:"tokens"`
+    HMACKeys map[string]string `json:"hmacKeys" yaml:"hmacKeys"`
+}
+
+// NewAuthConfig reads an auth config written as JSON.
+func NewAuthConfig(rdr io.Reader) (AuthConfig, error) {
+    var config AuthConfig
+
+    if err := json.NewDecoder(rdr).Decode(&config); err != nil {
+        return AuthConfig{}, fmt.Errorf("problem parsing auth config, %v", err)
+    }
+
+    return config, nil
+}
+
+// NewAuthConfigFromYAML reads an auth config written as YAML.
+func NewAuthConfigFromYAML(rdr io.Reader) (AuthConfig, error) {
+    var config AuthConfig
+
+    if err := yaml.NewDecoder(rdr).Decode(&config); err != nil {
+        return AuthConfig{}, fmt.Errorf("problem parsing auth config, %v", err)
+    }
+
+    return config, nil
+}
+
+// AuthConfigFromFile loads an auth config from a .json, .yaml or .yml file.
+func AuthConfigFromFile(path string) (AuthConfig, error) {
+    file, err := os.Open(path)
+
+    if err != nil {
+        return AuthConfig{}, fmt.Errorf("problem opening %s %v", path, err)
+    }
+    defer file.Close()
+
+    var config AuthConfig
+
+    switch ext := filepath.Ext(path); ext {
+    case ".yaml", ".yml":
+        config, err = NewAuthConfigFromYAML(file)
+    default:
+        config, err = NewAuthConfig(file)
+    }
+
+    if err != nil {
+        return AuthConfig{}, fmt.Errorf("problem loading auth config from file %s, %v", path, err)
+    }
+
+    return config, nil
+}
+
+// Authenticator lets through requests that carry an API token or a valid
+// HMAC signature from its config. It remembers the nonces of signed requests
+// for as long as their timestamps are fresh, so each is let through once.
+type Authenticator struct {
+    tokens map[string]string
+    keys   map[string][]byte
+    now    func() time.Time
+
+    mu     sync.Mutex
+    nonces map[string]time.Time
+}
+
+// NewAuthenticator checks config has at least one token or key, and none of
+// them empty.
+func NewAuthenticator(config AuthConfig) (*Authenticator, error) {
+    if len(config.Tokens) == 0 && len(config.HMACKeys) == 0 {
+        return nil, fmt.Errorf("%w, it has no tokens or HMAC keys", ErrBadAuthConfig)
+    }
+
+    a := &Authenticator{
+        tokens: map[string]string{},
+        keys:   map[string][]byte{},
+        now:    time.Now,
+        nonces: map[string]time.Time{},
+    }
+
+    for name, token := range config.Tokens {
+        if token == "" {
+            return nil, fmt.Errorf("%w, the token for %q is empty", ErrBadAuthConfig, name)
+        }
+        if other, taken := a.tokens[token]; taken {
+            return nil, fmt.Errorf("%w, %q and %q have the same token", ErrBadAuthConfig, other, name)
+        }
+        a.tokens[token] = name
+    }
+
+    for id, secret := range config.HMACKeys {
+        if secret == "" {
+            return nil, fmt.Errorf("%w, the HMAC key %q is empty", ErrBadAuthConfig, id)
+        }
+        a.keys[id] = []byte(secret)
+    }
+
+    return a, nil
+}
+
+// Protect makes every request to next that changes something, and every
+// WebSocket controlling a game over /ws, authenticate first. Anything else,
+// like GET /league or watching a game, stays public.
+func (a *Authenticator) Protect(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if needsAuth(r) {
+            if _, err := a.Authenticate(r); err != nil {
+                w.Header().Set("www-authenticate", `Bearer, HMAC`)
+                writeError(w, http.StatusUnauthorized, err.Error())
+                return
+            }
+        }
+
+        next.ServeHTTP(w, r)
+    })
+}
+
+func needsAuth(r *http.Request) bool {
+    switch r.Method {
+    case http.MethodGet, http.MethodHead, http.MethodOptions:
+        return r.URL.Path == "/ws"
+    default:
+        return true
+    }
+}
+
+// Authenticate returns the name of the token, or the ID of the key, r was
+// sent with. Browsers can't set headers on WebSockets, so a WebSocket can
+// send its token as the token query parameter instead.
+func (a *Authenticator) Authenticate(r *http.Request) (string, error) {
+    scheme, credentials, _ := strings.Cut(r.Header.Get("authorization"), " ")
+
+    switch {
+    case strings.EqualFold(scheme, "Bearer"):
+        return a.checkToken(credentials)
+    case strings.EqualFold(scheme, "HMAC"):
+        return a.checkSignature(r, credentials)
+    case scheme == "" && websocket.IsWebSocketUpgrade(r) && r.URL.Query().Get("token") != "":
+        return a.checkToken(r.URL.Query().Get("token"))
+    default:
+        return "", ErrNoCredentials
+    }
+}
+
+func (a *Authenticator) checkToken(token string) (string, error) {
+    for known, name := range a.tokens {
+        if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+            return name, nil
+        }
+    }
+    return "", ErrBadToken
+}
+
+// checkSignature checks credentials of the form "<key ID>:<signature>".
+func (a *Authenticator) checkSignature(r *http.Request, credentials string) (string, error) {
+    id, signature, found := strings.Cut(credentials, ":")
+    if !found {
+        return "", fmt.Errorf("%w, want HMAC <key ID>:<signature>", ErrBadSignature)
+    }
+
+    secret, known := a.keys[id]
+    if !known {
+        return "", fmt.Errorf("%w, unknown key %q", ErrBadSignature, id)
+    }
+
+    timestamp := r.Header.Get(TimestampHeader)
+    signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+    if err != nil {
+        return "", fmt.Errorf("%w, %s %q is not a Unix time", ErrBadSignature, TimestampHeader, timestamp)
+    }
+
+    if age := a.now().Sub(time.Unix(signedAt, 0)); age > MaxSignatureAge || age < -MaxSignatureAge {
+        return "", ErrStaleSignature
+    }
+
+    nonce := r.Header.Get(NonceHeader)
+    if nonce == "" {
+        return "", fmt.Errorf("%w, no %s", ErrBadSignature, NonceHeader)
+    }
+
+    want, err := requestMAC(r, timestamp, nonce, secret)
+    if err != nil {
+        return "", err
+    }
+
+    got, err := hex.DecodeString(signature)
+    if err != nil || !hmac.Equal(got, want) {
+        return "", ErrBadSignature
+    }
+
+    if !a.useNonce(id+":"+nonce, time.Unix(signedAt, 0).Add(MaxSignatureAge)) {
+        return "", ErrReplayedSignature
+    }
+
+    return id, nil
+}
+
+// useNonce reports whether nonce hasn't been used yet, remembering it until
+// expires, after which its request's timestamp is too old to be let through
+// anyway.
+func (a *Authenticator) useNonce(nonce string, expires time.Time) bool {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+
+    now := a.now()
+    for seen, expiry := range a.nonces {
+        if now.After(expiry) {
+            delete(a.nonces, seen)
+        }
+    }
+
+    if _, seen := a.nonces[nonce]; seen {
+        return false
+    }
+
+    a.nonces[nonce] = expires
+    return true
+}
+
+// SignRequest signs r with the HMAC key secret, whose ID is id, as of at.
+// The signature is the hex HMAC-SHA256 of the method, the path and query,
+// the Unix time, a random nonce and the hex SHA-256 of the body, each on its
+// own line, sent as "Authorization: HMAC <id>:<signature>" with the time in
+// TimestampHeader and the nonce in NonceHeader. A signed request is let
+// through once.
+func SignRequest(r *http.Request, id, secret string, at time.Time) error {
+    timestamp := strconv.FormatInt(at.Unix(), 10)
+
+    random := make([]byte, 16)
+    if _, err := rand.Read(random); err != nil {
+        return fmt.Errorf("problem making a nonce, %v", err)
+    }
+    nonce := hex.EncodeToString(random)
+
+    mac, err := requestMAC(r, timestamp, nonce, []byte(secret))
+    if err != nil {
+        return err
+    }
+
+    r.Header.Set(TimestampHeader, timestamp)
+    r.Header.Set(NonceHeader, nonce)
+    r.Header.Set("authorization", fmt.Sprintf("HMAC %s:%s", id, hex.EncodeToString(mac)))
+    return nil
+}
+
+// requestMAC reads r's body to sign it, putting it back to be read again.
+func requestMAC(r *http.Request, timestamp, nonce string, secret []byte) ([]byte, error) {
+    var body []byte
+
+    if r.Body != nil {
+        var err error
+        body, err = io.ReadAll(io.LimitReader(r.Body, maxSignedBody+1))
+        r.Body.Close()
+
+        if err != nil {
+            return nil, fmt.Errorf("problem reading body to sign, %v", err)
+        }
+        if len(body) > maxSignedBody {
+            return nil, fmt.Errorf("%w, bodies over %d bytes can't be signed", ErrBadSignature, maxSignedBody)
+        }
+
+        r.Body = io.NopCloser(bytes.NewReader(body))
+    }
+
+    bodyHash := sha256.Sum256(body)
+
+    mac := hmac.New(sha256.New, secret)
+    fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%s", r.Method, r.URL.RequestURI(), timestamp, nonce, hex.EncodeToString(bodyHash[:]))
+    return mac.Sum(nil), nil
+}
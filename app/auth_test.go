@@ -0,0 +1,258 @@
+package poker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuth(t *testing.T) {
+    config := AuthConfig{
+        Tokens:   map[string]string{"front-desk": "letmein"},
+        HMACKeys: map[string]string{"scoreboard": "s3cret"},
+    }
+    signedAt := time.Date(2021, 2, 18, 20, 0, 0, 0, time.UTC)
+
+    newProtected := func(t *testing.T) (http.Handler, *InMemoryPlayerStore) {
+        t.Helper()
+
+        auth, err := NewAuthenticator(config)
+        assertNoError(t, err)
+        auth.now = func() time.Time { return signedAt.Add(time.Minute) }
+
+        store := NewInMemoryPlayerStore(Player{"Pepper", 20, 0})
+        return auth.Protect(mustMakePlayerServer(t, store, DummyGame)), store
+    }
+
+    t.Run("leaves the league and scores public", func(t *testing.T) {
+        server, _ := newProtected(t)
+
+        for _, path := range []string{"/league", "/players/Pepper", "/games"} {
+            request, _ := http.NewRequest(http.MethodGet, path, nil)
+            response := httptest.NewRecorder()
+
+            server.ServeHTTP(response, request)
+
+            assertStatus(t, response, http.StatusOK)
+        }
+    })
+
+    t.Run("rejects changes without credentials", func(t *testing.T) {
+        server, store := newProtected(t)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newPostWinRequest("Pepper"))
+
+        assertAPIError(t, response, http.StatusUnauthorized, ErrNoCredentials.Error())
+        if got := response.Header().Get("www-authenticate"); got != "Bearer, HMAC" {
+            t.Errorf("got WWW-Authenticate %q, want %q", got, "Bearer, HMAC")
+        }
+        assertContractScore(t, store, "Pepper", 20)
+    })
+
+    t.Run("lets through changes with an API token", func(t *testing.T) {
+        server, store := newProtected(t)
+        request := newPostWinRequest("Pepper")
+        request.Header.Set("authorization", "Bearer letmein")
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        assertStatus(t, response, http.StatusAccepted)
+        assertContractScore(t, store, "Pepper", 21)
+    })
+
+    t.Run("rejects unknown API tokens", func(t *testing.T) {
+        server, _ := newProtected(t)
+        request := newPostWinRequest("Pepper")
+        request.Header.Set("authorization", "Bearer letmeout")
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        assertAPIError(t, response, http.StatusUnauthorized, ErrBadToken.Error())
+    })
+
+    t.Run("lets through signed changes, body and all", func(t *testing.T) {
+        server, _ := newProtected(t)
+        request := newPlayerAdminRequest(http.MethodPost, "Pepper/rename", `{"name": "Salt"}`)
+        assertNoError(t, SignRequest(request, "scoreboard", "s3cret", signedAt))
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        assertStatus(t, response, http.StatusOK)
+    })
+
+    t.Run("rejects the same signed request sent twice", func(t *testing.T) {
+        server, store := newProtected(t)
+        request := newPostWinRequest("Pepper")
+        assertNoError(t, SignRequest(request, "scoreboard", "s3cret", signedAt))
+        replayed := request.Clone(request.Context())
+
+        response := httptest.NewRecorder()
+        server.ServeHTTP(response, request)
+        assertStatus(t, response, http.StatusAccepted)
+
+        response = httptest.NewRecorder()
+        server.ServeHTTP(response, replayed)
+
+        assertAPIError(t, response, http.StatusUnauthorized, ErrReplayedSignature.Error())
+        assertContractScore(t, store, "Pepper", 21)
+    })
+
+    t.Run("protects controlling games over the WebSocket", func(t *testing.T) {
+        server, _ := newProtected(t)
+
+        for _, path := range []string{"/ws", "/ws?token=nope"} {
+            request := newWebSocketUpgradeRequest(path)
+            response := httptest.NewRecorder()
+
+            server.ServeHTTP(response, request)
+
+            assertStatus(t, response, http.StatusUnauthorized)
+        }
+    })
+
+    rejected := map[string]struct {
+        sign func(r *http.Request)
+        want error
+    }{
+        "a tampered body": {
+            func(r *http.Request) {
+                SignRequest(r, "scoreboard", "s3cret", signedAt)
+                r.Body = newPlayerAdminRequest(http.MethodPost, "Pepper/rename", `{"name": "Pepper2"}`).Body
+            },
+            ErrBadSignature,
+        },
+        "the wrong secret": {
+            func(r *http.Request) { SignRequest(r, "scoreboard", "guess", signedAt) },
+            ErrBadSignature,
+        },
+        "an unknown key": {
+            func(r *http.Request) { SignRequest(r, "lobby", "s3cret", signedAt) },
+            ErrBadSignature,
+        },
+        "an old timestamp": {
+            func(r *http.Request) { SignRequest(r, "scoreboard", "s3cret", signedAt.Add(-MaxSignatureAge)) },
+            ErrStaleSignature,
+        },
+        "no timestamp": {
+            func(r *http.Request) {
+                SignRequest(r, "scoreboard", "s3cret", signedAt)
+                r.Header.Del(TimestampHeader)
+            },
+            ErrBadSignature,
+        },
+        "no nonce": {
+            func(r *http.Request) {
+                SignRequest(r, "scoreboard", "s3cret", signedAt)
+                r.Header.Del(NonceHeader)
+            },
+            ErrBadSignature,
+        },
+        "a changed nonce": {
+            func(r *http.Request) {
+                SignRequest(r, "scoreboard", "s3cret", signedAt)
+                r.Header.Set(NonceHeader, "0123456789abcdef")
+            },
+            ErrBadSignature,
+        },
+        "a token in the query that isn't a WebSocket": {
+            func(r *http.Request) { r.URL.RawQuery = "token=letmein" },
+            ErrNoCredentials,
+        },
+    }
+
+    for name, c := range rejected {
+        t.Run("rejects "+name, func(t *testing.T) {
+            server, _ := newProtected(t)
+            request := newPlayerAdminRequest(http.MethodPost, "Pepper/rename", `{"name": "Salt"}`)
+            c.sign(request)
+            response := httptest.NewRecorder()
+
+            server.ServeHTTP(response, request)
+
+            assertStatus(t, response, http.StatusUnauthorized)
+        })
+
+        t.Run("says why it rejects "+name, func(t *testing.T) {
+            auth, _ := NewAuthenticator(config)
+            auth.now = func() time.Time { return signedAt.Add(time.Minute) }
+            request := newPlayerAdminRequest(http.MethodPost, "Pepper/rename", `{"name": "Salt"}`)
+            c.sign(request)
+
+            if _, err := auth.Authenticate(request); !errors.Is(err, c.want) {
+                t.Errorf("got error %v, want %v", err, c.want)
+            }
+        })
+    }
+
+    t.Run("names who sent a request", func(t *testing.T) {
+        auth, _ := NewAuthenticator(config)
+        auth.now = func() time.Time { return signedAt }
+
+        withToken := newWebSocketUpgradeRequest("/ws?token=letmein")
+        signed := newPostWinRequest("Pepper")
+        SignRequest(signed, "scoreboard", "s3cret", signedAt)
+
+        for want, request := range map[string]*http.Request{"front-desk": withToken, "scoreboard": signed} {
+            got, err := auth.Authenticate(request)
+            assertNoError(t, err)
+
+            if got != want {
+                t.Errorf("got %q, want %q", got, want)
+            }
+        }
+    })
+}
+
+func TestAuthConfig(t *testing.T) {
+    t.Run("loads tokens and keys from YAML", func(t *testing.T) {
+        path := filepath.Join(t.TempDir(), "auth.yaml")
+        os.WriteFile(path, []byte("tokens:\n  front-desk: letmein\nhmacKeys:\n  scoreboard: s3cret\n"), 0600)
+
+        got, err := AuthConfigFromFile(path)
+        assertNoError(t, err)
+
+        if got.Tokens["front-desk"] != "letmein" || got.HMACKeys["scoreboard"] != "s3cret" {
+            t.Errorf("got %+v", got)
+        }
+    })
+
+    t.Run("loads tokens and keys from JSON", func(t *testing.T) {
+        got, err := NewAuthConfig(strings.NewReader(`{"tokens": {"front-desk": "letmein"}}`))
+        assertNoError(t, err)
+
+        if got.Tokens["front-desk"] != "letmein" {
+            t.Errorf("got %+v", got)
+        }
+    })
+
+    bad := map[string]AuthConfig{
+        "no credentials":    {},
+        "an empty token":    {Tokens: map[string]string{"front-desk": ""}},
+        "a shared token":    {Tokens: map[string]string{"front-desk": "letmein", "bar": "letmein"}},
+        "an empty HMAC key": {HMACKeys: map[string]string{"scoreboard": ""}},
+    }
+
+    for name, config := range bad {
+        t.Run("rejects "+name, func(t *testing.T) {
+            if _, err := NewAuthenticator(config); !errors.Is(err, ErrBadAuthConfig) {
+                t.Errorf("got error %v, want %v", err, ErrBadAuthConfig)
+            }
+        })
+    }
+}
+
+func newWebSocketUpgradeRequest(path string) *http.Request {
+    req, _ := http.NewRequest(http.MethodGet, path, nil)
+    req.Header.Set("connection", "upgrade")
+    req.Header.Set("upgrade", "websocket")
+    return req
+}
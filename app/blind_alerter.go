@@ -1,23 +1,91 @@
 package poker
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"time"
 )
 
+// BlindAlert announces that the blinds have gone up to a level, counting
+// levels from 1.
+type BlindAlert struct {
+    Level int
+    BlindLevel
+}
+
+func (a BlindAlert) String() string {
+    alert := fmt.Sprintf("Level %d, blinds %d/%d", a.Level, a.SmallBlind, a.BigBlind)
+
+    if a.Ante > 0 {
+        alert += fmt.Sprintf(" ante %d", a.Ante)
+    }
+
+    return alert
+}
+
+// BlindAlertWriter is an io.Writer that takes blind alerts whole, rather
+// than as a line of text, so it can pass on the level's blinds as they are.
+type BlindAlertWriter interface {
+    io.Writer
+    WriteBlindAlert(alert BlindAlert) error
+}
+
 type BlindAlerter interface {
-    ScheduleAlertAt(duration time.Duration, amount int, to io.Writer)
+    ScheduleAlertAt(ctx context.Context, duration time.Duration, alert BlindAlert, to io.Writer)
 }
 
-type BlindAlerterFunc func(duration time.Duration, amount int, to io.Writer)
+type BlindAlerterFunc func(ctx context.Context, duration time.Duration, alert BlindAlert, to io.Writer)
 
-func (a BlindAlerterFunc) ScheduleAlertAt(duration time.Duration, amount int, to io.Writer) {
-    a(duration, amount, to)
+func (a BlindAlerterFunc) ScheduleAlertAt(ctx context.Context, duration time.Duration, alert BlindAlert, to io.Writer) {
+    a(ctx, duration, alert, to)
 }
 
-func Alerter(duration time.Duration, amount int, to io.Writer) {
-    time.AfterFunc(duration, func() {
-        fmt.Fprintf(to, "Blind is now %d\n", amount)
+// Alerter writes the alert to the destination once duration has passed,
+// unless ctx is cancelled first. A BlindAlertWriter gets the alert whole.
+func Alerter(ctx context.Context, duration time.Duration, alert BlindAlert, to io.Writer) {
+    timer := time.AfterFunc(duration, func() {
+        if ctx.Err() != nil {
+            return
+        }
+        writeBlindAlert(to, alert)
     })
-}
\ No newline at end of file
+
+    go func() {
+        <-ctx.Done()
+        timer.Stop()
+    }()
+}
+
+func writeBlindAlert(to io.Writer, alert BlindAlert) {
+    if w, ok := to.(BlindAlertWriter); ok {
+        w.WriteBlindAlert(alert)
+        return
+    }
+
+    fmt.Fprintln(to, alert)
+}
+
+// blindAlertWriters hands each blind alert to all of its writers, like
+// io.MultiWriter, but whole.
+type blindAlertWriters []BlindAlertWriter
+
+func (w blindAlertWriters) Write(p []byte) (n int, err error) {
+    for _, to := range w {
+        if n, err = to.Write(p); err != nil {
+            return n, err
+        }
+    }
+
+    return len(p), nil
+}
+
+func (w blindAlertWriters) WriteBlindAlert(alert BlindAlert) error {
+    for _, to := range w {
+        if err := to.WriteBlindAlert(alert); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,58 @@
+package poker
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAlerter(t *testing.T) {
+    t.Run("writes the blinds once the duration has passed", func(t *testing.T) {
+        out := &syncBuffer{}
+
+        Alerter(context.Background(), time.Millisecond, BlindAlert{2, BlindLevel{SmallBlind: 100, BigBlind: 200, Ante: 25}}, out)
+
+        passed := retryUntil(500*time.Millisecond, func() bool {
+            return out.String() == "Level 2, blinds 100/200 ante 25\n"
+        })
+
+        if !passed {
+            t.Errorf("got %q, want the blind alert", out.String())
+        }
+    })
+
+    t.Run("does not write once the context is cancelled", func(t *testing.T) {
+        out := &syncBuffer{}
+        ctx, cancel := context.WithCancel(context.Background())
+
+        Alerter(ctx, 5*time.Millisecond, BlindAlert{1, BlindLevel{SmallBlind: 100, BigBlind: 200}}, out)
+        cancel()
+
+        time.Sleep(20 * time.Millisecond)
+
+        if out.String() != "" {
+            t.Errorf("got %q written after cancelling, want nothing", out.String())
+        }
+    })
+}
+
+// syncBuffer is a bytes.Buffer that can be written to from the alert's timer
+// goroutine while the test reads it.
+type syncBuffer struct {
+    mu  sync.Mutex
+    buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return b.buf.String()
+}
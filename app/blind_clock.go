@@ -0,0 +1,268 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ClockCommand is an instruction to a running game's blind clock.
+type ClockCommand string
+
+const (
+    PauseClock   ClockCommand = "pause"
+    ResumeClock  ClockCommand = "resume"
+    AdvanceClock ClockCommand = "advance"
+    RewindClock  ClockCommand = "rewind"
+)
+
+// ClockCommands are the commands understood by ControlClock, keyed by the
+// keyword that issues them.
+var ClockCommands = map[string]ClockCommand{
+    string(PauseClock):   PauseClock,
+    string(ResumeClock):  ResumeClock,
+    string(AdvanceClock): AdvanceClock,
+    "next":               AdvanceClock,
+    string(RewindClock):  RewindClock,
+    "back":               RewindClock,
+}
+
+var (
+    ErrNoGameRunning   = errors.New("no game is running")
+    ErrClockPaused     = errors.New("the blind clock is already paused")
+    ErrClockRunning    = errors.New("the blind clock is not paused")
+    ErrLastBlindLevel  = errors.New("the blinds are already at the last level")
+    ErrFirstBlindLevel = errors.New("the blinds are already at the first level")
+    ErrUnknownCommand  = errors.New("unknown blind clock command")
+)
+
+// ClockState is where a blind clock is at.
+type ClockState struct {
+    Level      int
+    SmallBlind int
+    BigBlind   int
+    Ante       int
+    Remaining  time.Duration
+    Paused     bool
+}
+
+func (s ClockState) String() string {
+    state := fmt.Sprintf("Level %d, blinds %d/%d", s.Level, s.SmallBlind, s.BigBlind)
+
+    if s.Ante > 0 {
+        state += fmt.Sprintf(" ante %d", s.Ante)
+    }
+
+    state += fmt.Sprintf(", %v remaining", s.Remaining.Round(time.Second))
+
+    if s.Paused {
+        state += " (paused)"
+    }
+
+    return state
+}
+
+// BlindClock keeps time for a game's blind levels. It schedules an alert for
+// each level still to come with its BlindAlerter, rescheduling them whenever
+// the clock is paused, resumed or moved to another level.
+type BlindClock struct {
+    mu sync.Mutex
+
+    ctx       context.Context
+    alerter   BlindAlerter
+    to        io.Writer
+    levels    []BlindLevel
+    durations []time.Duration
+    now       func() time.Time
+
+    level     int
+    elapsed   time.Duration
+    startedAt time.Time
+    paused    bool
+    cancel    context.CancelFunc
+}
+
+// NewBlindClock creates a clock for blinds played by numberOfPlayers. Its
+// alerts stop when ctx is done.
+func NewBlindClock(ctx context.Context, alerter BlindAlerter, blinds BlindStructure, numberOfPlayers int, to io.Writer) *BlindClock {
+    durations := make([]time.Duration, len(blinds.Levels))
+    for i, level := range blinds.Levels {
+        durations[i] = level.LevelDuration(numberOfPlayers)
+    }
+
+    return &BlindClock{
+        ctx:       ctx,
+        alerter:   alerter,
+        to:        to,
+        levels:    blinds.Levels,
+        durations: durations,
+        now:       time.Now,
+        paused:    true,
+    }
+}
+
+// Start runs the clock from the first level.
+func (c *BlindClock) Start() {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.level = 0
+    c.elapsed = 0
+    c.paused = false
+    c.startedAt = c.now()
+    c.schedule(true)
+}
+
+// Control carries out cmd and returns the state the clock is left in.
+func (c *BlindClock) Control(cmd ClockCommand) (ClockState, error) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.catchUp()
+
+    var err error
+
+    switch cmd {
+    case PauseClock:
+        err = c.pause()
+    case ResumeClock:
+        err = c.resume()
+    case AdvanceClock:
+        err = c.moveTo(c.level + 1)
+    case RewindClock:
+        err = c.moveTo(c.level - 1)
+    default:
+        err = fmt.Errorf("%w %q", ErrUnknownCommand, cmd)
+    }
+
+    return c.state(), err
+}
+
+// State returns the current level and the time left in it.
+func (c *BlindClock) State() ClockState {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.catchUp()
+    return c.state()
+}
+
+func (c *BlindClock) pause() error {
+    if c.paused {
+        return ErrClockPaused
+    }
+
+    c.elapsed += c.now().Sub(c.startedAt)
+    c.paused = true
+    c.unschedule()
+    return nil
+}
+
+func (c *BlindClock) resume() error {
+    if !c.paused {
+        return ErrClockRunning
+    }
+
+    c.paused = false
+    c.startedAt = c.now()
+    c.schedule(false)
+    return nil
+}
+
+func (c *BlindClock) moveTo(level int) error {
+    if level >= len(c.levels) {
+        return ErrLastBlindLevel
+    }
+    if level < 0 {
+        return ErrFirstBlindLevel
+    }
+
+    c.level = level
+    c.elapsed = 0
+    c.startedAt = c.now()
+    c.schedule(true)
+    return nil
+}
+
+// catchUp moves the clock on to the level it should be at now. The caller
+// must hold c.mu.
+func (c *BlindClock) catchUp() {
+    if c.paused {
+        return
+    }
+
+    now := c.now()
+    c.elapsed += now.Sub(c.startedAt)
+    c.startedAt = now
+
+    for c.level < len(c.levels)-1 && c.elapsed >= c.durations[c.level] {
+        c.elapsed -= c.durations[c.level]
+        c.level++
+    }
+}
+
+// schedule replaces any scheduled alerts with alerts for the levels after
+// the current one, plus an immediate alert for the current level if
+// announce is set. Levels after the current one are only scheduled while
+// the clock runs. The caller must hold c.mu.
+func (c *BlindClock) schedule(announce bool) {
+    c.unschedule()
+
+    if len(c.levels) == 0 {
+        return
+    }
+
+    ctx, cancel := context.WithCancel(c.ctx)
+    c.cancel = cancel
+
+    if announce {
+        c.alerter.ScheduleAlertAt(ctx, 0, c.alert(c.level), c.to)
+    }
+
+    if c.paused {
+        return
+    }
+
+    at := c.durations[c.level] - c.elapsed
+    for i := c.level + 1; i < len(c.levels); i++ {
+        c.alerter.ScheduleAlertAt(ctx, at, c.alert(i), c.to)
+        at += c.durations[i]
+    }
+}
+
+// alert is the alert for the level at index level.
+func (c *BlindClock) alert(level int) BlindAlert {
+    return BlindAlert{level + 1, c.levels[level]}
+}
+
+func (c *BlindClock) unschedule() {
+    if c.cancel != nil {
+        c.cancel()
+        c.cancel = nil
+    }
+}
+
+func (c *BlindClock) state() ClockState {
+    if len(c.levels) == 0 {
+        return ClockState{Paused: c.paused}
+    }
+
+    level := c.levels[c.level]
+
+    remaining := c.durations[c.level] - c.elapsed
+    if remaining < 0 {
+        remaining = 0
+    }
+
+    return ClockState{
+        Level:      c.level + 1,
+        SmallBlind: level.SmallBlind,
+        BigBlind:   level.BigBlind,
+        Ante:       level.Ante,
+        Remaining:  remaining,
+        Paused:     c.paused,
+    }
+}
@@ -0,0 +1,194 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBlindClock(t *testing.T) {
+    blinds := BlindStructure{
+        Name: "test",
+        Levels: []BlindLevel{
+            {SmallBlind: 100, BigBlind: 200, Duration: 10 * time.Minute},
+            {SmallBlind: 200, BigBlind: 400, Duration: 10 * time.Minute},
+            {SmallBlind: 300, BigBlind: 600, Ante: 50, Duration: 10 * time.Minute},
+        },
+    }
+
+    t.Run("moves through the levels as time passes", func(t *testing.T) {
+        clock, _, now := newTestBlindClock(blinds)
+        clock.Start()
+
+        now.add(25 * time.Minute)
+
+        assertClockState(t, clock.State(), ClockState{Level: 3, SmallBlind: 300, BigBlind: 600, Ante: 50, Remaining: 5 * time.Minute})
+    })
+
+    t.Run("alerts announce each level's blinds and ante", func(t *testing.T) {
+        clock, alerter, _ := newTestBlindClock(blinds)
+        clock.Start()
+
+        got := alerter.alerts[len(alerter.alerts)-1].Alert
+        want := BlindAlert{3, blinds.Levels[2]}
+
+        if got != want {
+            t.Errorf("got alert %+v, want %+v", got, want)
+        }
+    })
+
+    t.Run("pausing stops the clock and cancels the alerts", func(t *testing.T) {
+        clock, alerter, now := newTestBlindClock(blinds)
+        clock.Start()
+
+        now.add(3 * time.Minute)
+        state, err := clock.Control(PauseClock)
+
+        assertNoError(t, err)
+        assertCancelled(t, alerter.ctx)
+
+        want := ClockState{Level: 1, SmallBlind: 100, BigBlind: 200, Remaining: 7 * time.Minute, Paused: true}
+        assertClockState(t, state, want)
+
+        now.add(time.Hour)
+        assertClockState(t, clock.State(), want)
+    })
+
+    t.Run("resuming schedules the remaining levels from where the clock stopped", func(t *testing.T) {
+        clock, alerter, now := newTestBlindClock(blinds)
+        clock.Start()
+
+        now.add(3 * time.Minute)
+        clock.Control(PauseClock)
+        now.add(time.Hour)
+
+        scheduled := len(alerter.alerts)
+        _, err := clock.Control(ResumeClock)
+
+        assertNoError(t, err)
+        assertNotCancelled(t, alerter.ctx)
+        assertAlertsScheduled(t, alerter.alerts[scheduled:], []ScheduledAlert{
+            {At: 7 * time.Minute, Amount: 200},
+            {At: 17 * time.Minute, Amount: 300},
+        })
+    })
+
+    t.Run("advancing announces the next level straight away", func(t *testing.T) {
+        clock, alerter, now := newTestBlindClock(blinds)
+        clock.Start()
+
+        now.add(3 * time.Minute)
+        scheduled := len(alerter.alerts)
+        state, err := clock.Control(AdvanceClock)
+
+        assertNoError(t, err)
+        assertClockState(t, state, ClockState{Level: 2, SmallBlind: 200, BigBlind: 400, Remaining: 10 * time.Minute})
+        assertAlertsScheduled(t, alerter.alerts[scheduled:], []ScheduledAlert{
+            {At: 0, Amount: 200},
+            {At: 10 * time.Minute, Amount: 300},
+        })
+    })
+
+    t.Run("rewinding goes back to the start of the previous level", func(t *testing.T) {
+        clock, _, now := newTestBlindClock(blinds)
+        clock.Start()
+
+        now.add(15 * time.Minute)
+        state, err := clock.Control(RewindClock)
+
+        assertNoError(t, err)
+        assertClockState(t, state, ClockState{Level: 1, SmallBlind: 100, BigBlind: 200, Remaining: 10 * time.Minute})
+    })
+
+    t.Run("moving while paused stays paused", func(t *testing.T) {
+        clock, alerter, _ := newTestBlindClock(blinds)
+        clock.Start()
+        clock.Control(PauseClock)
+
+        scheduled := len(alerter.alerts)
+        state, err := clock.Control(AdvanceClock)
+
+        assertNoError(t, err)
+        if !state.Paused {
+            t.Error("expected the clock to still be paused")
+        }
+        assertAlertsScheduled(t, alerter.alerts[scheduled:], []ScheduledAlert{
+            {At: 0, Amount: 200},
+        })
+    })
+
+    t.Run("reports commands that make no sense", func(t *testing.T) {
+        cases := []struct {
+            name  string
+            setup []ClockCommand
+            cmd   ClockCommand
+            want  error
+        }{
+            {"pause when paused", []ClockCommand{PauseClock}, PauseClock, ErrClockPaused},
+            {"resume when running", nil, ResumeClock, ErrClockRunning},
+            {"rewind from the first level", nil, RewindClock, ErrFirstBlindLevel},
+            {"advance past the last level", []ClockCommand{AdvanceClock, AdvanceClock}, AdvanceClock, ErrLastBlindLevel},
+            {"unknown command", nil, ClockCommand("shuffle"), ErrUnknownCommand},
+        }
+
+        for _, c := range cases {
+            t.Run(c.name, func(t *testing.T) {
+                clock, _, _ := newTestBlindClock(blinds)
+                clock.Start()
+
+                for _, cmd := range c.setup {
+                    clock.Control(cmd)
+                }
+
+                _, err := clock.Control(c.cmd)
+
+                if !errors.Is(err, c.want) {
+                    t.Errorf("got error %v, want %v", err, c.want)
+                }
+            })
+        }
+    })
+}
+
+type fakeNow struct {
+    t time.Time
+}
+
+func (f *fakeNow) now() time.Time {
+    return f.t
+}
+
+func (f *fakeNow) add(d time.Duration) {
+    f.t = f.t.Add(d)
+}
+
+func newTestBlindClock(blinds BlindStructure) (*BlindClock, *SpyBlindAlerter, *fakeNow) {
+    alerter := &SpyBlindAlerter{}
+    now := &fakeNow{time.Date(2021, 2, 18, 20, 0, 0, 0, time.UTC)}
+
+    clock := NewBlindClock(context.Background(), alerter, blinds, 5, io.Discard)
+    clock.now = now.now
+
+    return clock, alerter, now
+}
+
+func assertClockState(t testing.TB, got, want ClockState) {
+    t.Helper()
+    if got != want {
+        t.Errorf("got clock state %+v, want %+v", got, want)
+    }
+}
+
+func assertAlertsScheduled(t testing.TB, got, want []ScheduledAlert) {
+    t.Helper()
+
+    if len(got) != len(want) {
+        t.Fatalf("got %d alerts scheduled %v, want %d %v", len(got), got, len(want), want)
+    }
+
+    for i := range want {
+        AssertScheduledAlert(t, got[i], want[i])
+    }
+}
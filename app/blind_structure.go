@@ -0,0 +1,172 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BlindLevel is a single step of a blind structure. A level with no Duration
+// lasts 5 minutes plus one minute per player.
+type BlindLevel struct {
+    SmallBlind int
+    BigBlind   int
+    Ante       int
+    Duration   time.Duration
+}
+
+// BlindStructure is the ladder of blind levels a game is played with.
+type BlindStructure struct {
+    Name   string
+    Levels []BlindLevel
+}
+
+// LevelDuration is how long the level lasts in a game with numberOfPlayers.
+func (l BlindLevel) LevelDuration(numberOfPlayers int) time.Duration {
+    if l.Duration > 0 {
+        return l.Duration
+    }
+    return time.Duration(5+numberOfPlayers) * time.Minute
+}
+
+var DefaultBlindStructure = BlindStructure{
+    Name: "default",
+    Levels: levelsFromSmallBlinds(0, 100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000),
+}
+
+var TurboBlindStructure = BlindStructure{
+    Name: "turbo",
+    Levels: levelsFromSmallBlinds(5*time.Minute, 100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000),
+}
+
+// BlindStructurePresets are the blind structures available without a config
+// file, keyed by name.
+var BlindStructurePresets = map[string]BlindStructure{
+    DefaultBlindStructure.Name: DefaultBlindStructure,
+    TurboBlindStructure.Name:   TurboBlindStructure,
+}
+
+func levelsFromSmallBlinds(duration time.Duration, smallBlinds ...int) []BlindLevel {
+    levels := make([]BlindLevel, len(smallBlinds))
+    for i, blind := range smallBlinds {
+        levels[i] = BlindLevel{SmallBlind: blind, BigBlind: 2 * blind, Duration: duration}
+    }
+    return levels
+}
+
+type blindLevelConfig struct {
+    SmallBlind int    `json:"smallBlind" yaml:"smallBlind"`
+    BigBlind   int    `json:"bigBlind" yaml:"bigBlind"`
+    Ante       int    `json:"ante" yaml:"ante"`
+    Duration   string `json:"duration" yaml:"duration"`
+}
+
+type blindStructureConfig struct {
+    Name   string             `json:"name" yaml:"name"`
+    Levels []blindLevelConfig `json:"levels" yaml:"levels"`
+}
+
+// NewBlindStructure reads a blind structure written as JSON.
+func NewBlindStructure(rdr io.Reader) (BlindStructure, error) {
+    var config blindStructureConfig
+
+    if err := json.NewDecoder(rdr).Decode(&config); err != nil {
+        return BlindStructure{}, fmt.Errorf("problem parsing blind structure, %v", err)
+    }
+
+    return config.blindStructure()
+}
+
+// NewBlindStructureFromYAML reads a blind structure written as YAML.
+func NewBlindStructureFromYAML(rdr io.Reader) (BlindStructure, error) {
+    var config blindStructureConfig
+
+    if err := yaml.NewDecoder(rdr).Decode(&config); err != nil {
+        return BlindStructure{}, fmt.Errorf("problem parsing blind structure, %v", err)
+    }
+
+    return config.blindStructure()
+}
+
+// BlindStructureFromFile loads a blind structure from a .json, .yaml or .yml
+// file. The structure is named after the file if the file doesn't name it.
+func BlindStructureFromFile(path string) (BlindStructure, error) {
+    file, err := os.Open(path)
+
+    if err != nil {
+        return BlindStructure{}, fmt.Errorf("problem opening %s %v", path, err)
+    }
+    defer file.Close()
+
+    var blinds BlindStructure
+
+    switch ext := filepath.Ext(path); ext {
+    case ".yaml", ".yml":
+        blinds, err = NewBlindStructureFromYAML(file)
+    default:
+        blinds, err = NewBlindStructure(file)
+    }
+
+    if err != nil {
+        return BlindStructure{}, fmt.Errorf("problem loading blind structure from file %s, %v", path, err)
+    }
+
+    if blinds.Name == "" {
+        blinds.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+    }
+
+    return blinds, nil
+}
+
+// LookupBlindStructure returns the preset called nameOrPath, or loads the
+// blind structure from the file at nameOrPath.
+func LookupBlindStructure(nameOrPath string) (BlindStructure, error) {
+    if blinds, ok := BlindStructurePresets[nameOrPath]; ok {
+        return blinds, nil
+    }
+    return BlindStructureFromFile(nameOrPath)
+}
+
+func (c blindStructureConfig) blindStructure() (BlindStructure, error) {
+    if len(c.Levels) == 0 {
+        return BlindStructure{}, fmt.Errorf("blind structure %q has no levels", c.Name)
+    }
+
+    blinds := BlindStructure{Name: c.Name}
+
+    for i, level := range c.Levels {
+        var duration time.Duration
+
+        if level.Duration != "" {
+            d, err := time.ParseDuration(level.Duration)
+            if err != nil {
+                return BlindStructure{}, fmt.Errorf("level %d has a bad duration, %v", i+1, err)
+            }
+            duration = d
+        }
+
+        if level.SmallBlind <= 0 {
+            return BlindStructure{}, fmt.Errorf("level %d needs a small blind", i+1)
+        }
+
+        bigBlind := level.BigBlind
+        if bigBlind == 0 {
+            bigBlind = 2 * level.SmallBlind
+        }
+
+        blinds.Levels = append(blinds.Levels, BlindLevel{
+            SmallBlind: level.SmallBlind,
+            BigBlind:   bigBlind,
+            Ante:       level.Ante,
+            Duration:   duration,
+        })
+    }
+
+    return blinds, nil
+}
@@ -0,0 +1,96 @@
+package poker
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlindStructure(t *testing.T) {
+    want := BlindStructure{
+        Name: "deep",
+        Levels: []BlindLevel{
+            {SmallBlind: 25, BigBlind: 50, Duration: 20 * time.Minute},
+            {SmallBlind: 50, BigBlind: 100, Ante: 10, Duration: 20 * time.Minute},
+            {SmallBlind: 75, BigBlind: 150, Ante: 15},
+        },
+    }
+
+    t.Run("reads a blind structure from JSON", func(t *testing.T) {
+        got, err := NewBlindStructure(strings.NewReader(`{
+            "name": "deep",
+            "levels": [
+                {"smallBlind": 25, "bigBlind": 50, "duration": "20m"},
+                {"smallBlind": 50, "bigBlind": 100, "ante": 10, "duration": "20m"},
+                {"smallBlind": 75, "ante": 15}
+            ]}`))
+
+        assertNoError(t, err)
+        assertBlindStructure(t, got, want)
+    })
+
+    t.Run("reads a blind structure from YAML", func(t *testing.T) {
+        got, err := NewBlindStructureFromYAML(strings.NewReader(`
+name: deep
+levels:
+  - {smallBlind: 25, bigBlind: 50, duration: 20m}
+  - {smallBlind: 50, bigBlind: 100, ante: 10, duration: 20m}
+  - {smallBlind: 75, ante: 15}
+`))
+
+        assertNoError(t, err)
+        assertBlindStructure(t, got, want)
+    })
+
+    t.Run("rejects bad structures", func(t *testing.T) {
+        cases := map[string]string{
+            "no levels":     `{"name": "empty", "levels": []}`,
+            "bad duration":  `{"levels": [{"smallBlind": 25, "duration": "soon"}]}`,
+            "no small blind": `{"levels": [{"bigBlind": 50}]}`,
+            "not json":      `blinds`,
+        }
+
+        for name, config := range cases {
+            t.Run(name, func(t *testing.T) {
+                _, err := NewBlindStructure(strings.NewReader(config))
+                if err == nil {
+                    t.Error("expected an error but didn't get one")
+                }
+            })
+        }
+    })
+
+    t.Run("loads a file named after the file", func(t *testing.T) {
+        file, clean := createTempFile(t, `{"levels": [{"smallBlind": 25, "duration": "5m"}]}`)
+        defer clean()
+
+        got, err := BlindStructureFromFile(file.Name())
+        assertNoError(t, err)
+
+        if want := filepath.Base(file.Name()); got.Name != want {
+            t.Errorf("got name %q, want %q", got.Name, want)
+        }
+    })
+
+    t.Run("looks up presets by name", func(t *testing.T) {
+        got, err := LookupBlindStructure("turbo")
+        assertNoError(t, err)
+        assertBlindStructure(t, got, TurboBlindStructure)
+    })
+
+    t.Run("default levels last five minutes plus one per player", func(t *testing.T) {
+        got := DefaultBlindStructure.Levels[0].LevelDuration(7)
+        if got != 12*time.Minute {
+            t.Errorf("got %v, want %v", got, 12*time.Minute)
+        }
+    })
+}
+
+func assertBlindStructure(t testing.TB, got, want BlindStructure) {
+    t.Helper()
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("got %+v want %+v", got, want)
+    }
+}
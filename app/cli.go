@@ -2,9 +2,9 @@ package poker
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
 )
 
@@ -12,43 +12,248 @@ type CLI struct {
     in          *bufio.Scanner
     out         io.Writer
     game        Game
+    store       PlayerStore
+    blinds      BlindStructure
+    minPlayers  int
+    maxPlayers  int
+
+    // cancel stops the running game's alerts, it is nil when no game runs
+    cancel      context.CancelFunc
+    players     []string
 }
 
-func NewCLI(in io.Reader, out io.Writer, game Game) *CLI {
+func NewCLI(in io.Reader, out io.Writer, game Game, store PlayerStore) *CLI {
     return &CLI{
         in:  bufio.NewScanner(in),
         out: out,
         game: game,
+        store: store,
+        blinds: DefaultBlindStructure,
+        minPlayers: DefaultMinPlayers,
+        maxPlayers: DefaultMaxPlayers,
     }
 }
 
-const PlayerPrompt = "Please enter the number of players: "
-const BadPlayerInputErrMsg = "Bad value received for number of players, please try again with a number"
+// UseBlindStructure sets the blind structure the CLI starts games with when
+// new game doesn't name one.
+func (cli *CLI) UseBlindStructure(blinds BlindStructure) {
+    cli.blinds = blinds
+}
+
+// UsePlayerRange sets how few and how many players a game can have.
+func (cli *CLI) UsePlayerRange(min, max int) {
+    cli.minPlayers = min
+    cli.maxPlayers = max
+}
+
+const PlayerPrompt = "Please enter the names of the players, separated by commas: "
+const NoGameRunningMsg = "No game is running, type new game to start one\n"
+const GameRunningMsg = "A game is already running, declare its winner first\n"
+const UnknownCommandMsg = "Unknown command, type help to see the commands\n"
+const GoodbyeMsg = "Bye!\n"
+
+const SessionHelp = `Commands:
+  new game [blinds]  start a game, with a preset or file's blind structure if given, e.g. new game turbo
+  {Name} wins        declare the winner, optionally followed by the other places, e.g. Chris wins, Cleo, Ruth
+  pause, resume      pause or resume the blind clock
+  next, back         move the blinds to the next or previous level
+  league             show the league table
+  score {Name}       show a player's wins
+  help               show this help
+  quit               leave
+`
+
+// Run plays games until the user quits or the input ends.
+func (cli *CLI) Run() {
+    defer cli.endGame()
+
+    for {
+        input, ok := cli.scanLine()
 
+        if !ok {
+            return
+        }
+
+        if quit := cli.do(strings.TrimSpace(input)); quit {
+            fmt.Fprint(cli.out, GoodbyeMsg)
+            return
+        }
+    }
+}
+
+// PlayPoker plays a single game, returning once its winner is declared or
+// the input ends.
 func (cli *CLI) PlayPoker() {
-    fmt.Fprint(cli.out, PlayerPrompt)
+    defer cli.endGame()
 
-    numberOfPlayersInput := cli.readLine()
-    numberOfPlayers, err := strconv.Atoi(strings.Trim(numberOfPlayersInput, "\n"))
+    if !cli.newGame("") {
+        return
+    }
+
+    for cli.cancel != nil {
+        input, ok := cli.scanLine()
 
-	if err != nil {
-		fmt.Fprint(cli.out, BadPlayerInputErrMsg)
-		return
-	}
+        if !ok {
+            return
+        }
+
+        if quit := cli.do(strings.TrimSpace(input)); quit {
+            return
+        }
+    }
+}
 
-    cli.game.Start(numberOfPlayers, cli.out)
+// do carries out a line of user input and reports whether the user asked
+// to quit.
+func (cli *CLI) do(input string) (quit bool) {
+    if cmd, ok := ClockCommands[input]; ok {
+        cli.controlClock(cmd)
+        return false
+    }
 
-    winnerInput := cli.readLine()
-    winner := extractWinner(winnerInput)
+    switch {
+    case input == "":
+    case input == "quit" || input == "exit":
+        return true
+    case input == "help":
+        fmt.Fprint(cli.out, SessionHelp)
+    case input == "new game" || strings.HasPrefix(input, "new game "):
+        cli.newGame(strings.TrimSpace(strings.TrimPrefix(input, "new game")))
+    case input == "league":
+        cli.showLeague()
+    case strings.HasPrefix(input, "score "):
+        cli.showScore(strings.TrimSpace(strings.TrimPrefix(input, "score ")))
+    case IsWinLine(input):
+        cli.finishGame(input)
+    default:
+        fmt.Fprint(cli.out, UnknownCommandMsg)
+    }
 
-    cli.game.Finish(winner)
+    return false
 }
 
-func extractWinner(userInput string) string {
-    return strings.Replace(userInput, " wins", "", 1)
+// newGame asks for the players' names until it gets a good roster and starts
+// a game, reporting whether it did. It gives up if the input ends. The game
+// uses the blind structure named blinds, see LookupBlindStructure, or the
+// CLI's if blinds is empty.
+func (cli *CLI) newGame(blinds string) bool {
+    if cli.cancel != nil {
+        fmt.Fprint(cli.out, GameRunningMsg)
+        return false
+    }
+
+    structure := cli.blinds
+
+    if blinds != "" {
+        var err error
+        if structure, err = LookupBlindStructure(blinds); err != nil {
+            fmt.Fprintf(cli.out, "%v\n", err)
+            return false
+        }
+    }
+
+    players, ok := cli.askForRoster()
+
+    if !ok {
+        return false
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cli.cancel = cancel
+    cli.players = players
+
+    cli.game.Start(ctx, players, structure, cli.out)
+    return true
 }
 
-func (cli *CLI) readLine() string {
-    cli.in.Scan()
-    return cli.in.Text()
-}
\ No newline at end of file
+func (cli *CLI) askForRoster() ([]string, bool) {
+    for {
+        fmt.Fprint(cli.out, PlayerPrompt)
+
+        input, ok := cli.scanLine()
+
+        if !ok {
+            return nil, false
+        }
+
+        players, err := ParseRoster(input, cli.minPlayers, cli.maxPlayers)
+
+        if err == nil {
+            return players, true
+        }
+
+        fmt.Fprintf(cli.out, "%v\n", err)
+    }
+}
+
+// finishGame declares the winner of the running game. If the declaration is
+// no good, or the result can't be recorded, the game carries on so the user
+// can try again.
+func (cli *CLI) finishGame(input string) {
+    if cli.cancel == nil {
+        fmt.Fprint(cli.out, NoGameRunningMsg)
+        return
+    }
+
+    winner, placings, err := ParseWinLine(input, cli.players)
+
+    if err != nil {
+        fmt.Fprintf(cli.out, "%v\n", err)
+        return
+    }
+
+    if err := cli.game.Finish(winner, placings...); err != nil {
+        fmt.Fprintf(cli.out, "%v\n", err)
+        return
+    }
+
+    cli.endGame()
+}
+
+// endGame stops the running game's alerts without declaring a winner.
+func (cli *CLI) endGame() {
+    if cli.cancel != nil {
+        cli.cancel()
+        cli.cancel = nil
+        cli.players = nil
+    }
+}
+
+// controlClock carries out a blind clock keyword typed while a game runs and
+// tells the user where the clock is at.
+func (cli *CLI) controlClock(cmd ClockCommand) {
+    state, err := cli.game.ControlClock(cmd)
+
+    if err != nil {
+        fmt.Fprintf(cli.out, "%v\n", err)
+        return
+    }
+
+    fmt.Fprintf(cli.out, "%v\n", state)
+}
+
+func (cli *CLI) showLeague() {
+    league := cli.store.GetLeague(WholeLeague)
+
+    if len(league) == 0 {
+        fmt.Fprintln(cli.out, "Nobody has won a game yet")
+        return
+    }
+
+    for _, standing := range league {
+        fmt.Fprintf(cli.out, "%d. %s %d\n", standing.Rank, standing.Name, standing.Wins)
+    }
+}
+
+func (cli *CLI) showScore(name string) {
+    fmt.Fprintf(cli.out, "%s has %d wins\n", name, cli.store.GetPlayerScore(name))
+}
+
+// scanLine reads the next line of input, reporting false once the input
+// has ended.
+func (cli *CLI) scanLine() (string, bool) {
+    if !cli.in.Scan() {
+        return "", false
+    }
+    return cli.in.Text(), true
+}
@@ -0,0 +1,152 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const DefaultMinPlayers = 2
+const DefaultMaxPlayers = 10
+
+var (
+    ErrNoPlayerName    = errors.New("a player has no name")
+    ErrTooFewPlayers   = errors.New("too few players")
+    ErrTooManyPlayers  = errors.New("too many players")
+    ErrBadWinLine      = errors.New("not a winner declaration")
+    ErrNoWinner        = errors.New("no winner named")
+    ErrDuplicatePlayer = errors.New("player named more than once")
+    ErrUnknownPlayer   = errors.New("player not in this game")
+)
+
+// InputError is user input that was rejected and can be asked for again. Err
+// is one of the Err values above saying what was wrong with it.
+type InputError struct {
+    Input string
+    Err   error
+    Hint  string
+}
+
+func (e *InputError) Error() string {
+    msg := fmt.Sprintf("%q is no good, %v", e.Input, e.Err)
+    if e.Hint != "" {
+        msg += ", " + e.Hint
+    }
+    return msg
+}
+
+func (e *InputError) Unwrap() error {
+    return e.Err
+}
+
+// ParseRoster reads the names of the players in a game, separated by commas,
+// e.g. "Chris, Cleo, Ruth". See ValidateRoster for what makes a good roster.
+func ParseRoster(input string, min, max int) ([]string, error) {
+    input = strings.TrimSpace(input)
+
+    var players []string
+
+    if input != "" {
+        for _, name := range strings.Split(input, ",") {
+            players = append(players, strings.TrimSpace(name))
+        }
+    }
+
+    if err := ValidateRoster(players, min, max); err != nil {
+        return nil, &InputError{input, err, fmt.Sprintf("please enter from %d to %d names separated by commas", min, max)}
+    }
+
+    return players, nil
+}
+
+// ValidateRoster checks there are between min and max players inclusive, all
+// with a name and none named twice.
+func ValidateRoster(players []string, min, max int) error {
+    seen := map[string]bool{}
+
+    for _, name := range players {
+        if strings.TrimSpace(name) == "" {
+            return ErrNoPlayerName
+        }
+        if seen[name] {
+            return fmt.Errorf("%w %q", ErrDuplicatePlayer, name)
+        }
+        seen[name] = true
+    }
+
+    switch {
+    case len(players) < min:
+        return ErrTooFewPlayers
+    case len(players) > max:
+        return ErrTooManyPlayers
+    }
+
+    return nil
+}
+
+// IsWinLine reports whether input looks like an attempt to declare a winner,
+// so it can be checked with ParseWinLine rather than treated as a command.
+func IsWinLine(input string) bool {
+    for _, word := range strings.Fields(strings.ReplaceAll(input, ",", " ")) {
+        if word == "win" || word == "wins" || word == "won" {
+            return true
+        }
+    }
+    return false
+}
+
+// ParseWinLine reads "{Name} wins", optionally followed by the places of the
+// other players best first, e.g. "Chris wins, Cleo, Ruth". Everyone placed
+// must be on the roster, and nobody can be placed twice.
+func ParseWinLine(input string, roster []string) (winner string, placings []string, err error) {
+    const hint = "type {Name} wins, optionally followed by the other places, e.g. Chris wins, Cleo, Ruth"
+
+    input = strings.TrimSpace(input)
+    head, rest := input, ""
+
+    if i := strings.Index(input, ","); i >= 0 {
+        head, rest = input[:i], input[i+1:]
+    }
+
+    head = strings.TrimSpace(head)
+
+    if head == "wins" {
+        return "", nil, &InputError{input, ErrNoWinner, hint}
+    }
+
+    if !strings.HasSuffix(head, " wins") {
+        return "", nil, &InputError{input, ErrBadWinLine, hint}
+    }
+
+    winner = strings.TrimSpace(strings.TrimSuffix(head, " wins"))
+
+    if winner == "" {
+        return "", nil, &InputError{input, ErrNoWinner, hint}
+    }
+
+    placed := map[string]bool{winner: true}
+
+    if rest != "" {
+        for _, name := range strings.Split(rest, ",") {
+            name = strings.TrimSpace(name)
+
+            if name == "" {
+                return "", nil, &InputError{input, ErrBadWinLine, hint}
+            }
+            if placed[name] {
+                return "", nil, &InputError{input, ErrDuplicatePlayer, hint}
+            }
+
+            placed[name] = true
+            placings = append(placings, name)
+        }
+    }
+
+    for _, name := range append([]string{winner}, placings...) {
+        if !containsName(roster, name) {
+            return "", nil, &InputError{input, fmt.Errorf("%w %q", ErrUnknownPlayer, name), "the players are " + strings.Join(roster, ", ")}
+        }
+    }
+
+    return winner, placings, nil
+}
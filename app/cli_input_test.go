@@ -0,0 +1,93 @@
+package poker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseRoster(t *testing.T) {
+    t.Run("reads the names of the players", func(t *testing.T) {
+        got, err := ParseRoster(" Chris, Cleo ,Ruth ", 2, 10)
+
+        assertNoError(t, err)
+        want := []string{"Chris", "Cleo", "Ruth"}
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("got %v, want %v", got, want)
+        }
+    })
+
+    cases := []struct {
+        input string
+        want  error
+    }{
+        {"", ErrTooFewPlayers},
+        {"Chris", ErrTooFewPlayers},
+        {"Chris, , Ruth", ErrNoPlayerName},
+        {"Chris, Ruth, Chris", ErrDuplicatePlayer},
+        {"A, B, C, D, E, F, G, H, I, J, K", ErrTooManyPlayers},
+    }
+
+    for _, c := range cases {
+        t.Run(c.input, func(t *testing.T) {
+            _, err := ParseRoster(c.input, 2, 10)
+            assertInputError(t, err, c.want)
+        })
+    }
+}
+
+func TestParseWinLine(t *testing.T) {
+    roster := []string{"Chris", "Cleo", "Ruth"}
+
+    t.Run("reads the winner", func(t *testing.T) {
+        winner, placings, err := ParseWinLine("Chris wins", roster)
+
+        assertNoError(t, err)
+        if winner != "Chris" || len(placings) != 0 {
+            t.Errorf("got %q %v, want Chris alone", winner, placings)
+        }
+    })
+
+    t.Run("reads the other places", func(t *testing.T) {
+        winner, placings, err := ParseWinLine("Cleo wins, Chris, Ruth", roster)
+
+        assertNoError(t, err)
+        if winner != "Cleo" || !reflect.DeepEqual(placings, []string{"Chris", "Ruth"}) {
+            t.Errorf("got %q %v, want Cleo then Chris and Ruth", winner, placings)
+        }
+    })
+
+    cases := []struct {
+        input string
+        want  error
+    }{
+        {"Chris win", ErrBadWinLine},
+        {"Chris wins Cleo", ErrBadWinLine},
+        {"Chris wins, , Ruth", ErrBadWinLine},
+        {"wins", ErrNoWinner},
+        {" wins, Ruth", ErrNoWinner},
+        {"Chris wins, Ruth, Chris", ErrDuplicatePlayer},
+        {"Chris wins, Cleo, Pies", ErrUnknownPlayer},
+        {"Pies wins", ErrUnknownPlayer},
+    }
+
+    for _, c := range cases {
+        t.Run(c.input, func(t *testing.T) {
+            _, _, err := ParseWinLine(c.input, roster)
+            assertInputError(t, err, c.want)
+        })
+    }
+}
+
+func assertInputError(t testing.TB, err, want error) {
+    t.Helper()
+
+    var inputErr *InputError
+    if !errors.As(err, &inputErr) {
+        t.Fatalf("got %v, want an *InputError", err)
+    }
+
+    if !errors.Is(err, want) {
+        t.Errorf("got error %v, want %v", err, want)
+    }
+}
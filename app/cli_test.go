@@ -2,7 +2,11 @@ package poker
 
 import (
 	"bytes"
+	"context"
+	"io"
+	"errors"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -10,12 +14,13 @@ import (
 
 func TestCLI(t *testing.T) {
     var dummyStdOut = &bytes.Buffer{}
+    var dummyStore = NewInMemoryPlayerStore()
     
     t.Run("record chris win from user input", func(t *testing.T) {
-        in := strings.NewReader("5\nChris wins\n")
+        in := strings.NewReader("Chris, Cleo, Ruth, Tiest, Apollo\nChris wins\n")
         game := &GameSpy{}
         
-        cli := NewCLI(in, dummyStdOut, game)
+        cli := NewCLI(in, dummyStdOut, game, dummyStore)
         cli.PlayPoker()
 
         if game.FinishedWith != "Chris" {
@@ -24,10 +29,10 @@ func TestCLI(t *testing.T) {
     })
 
     t.Run("record cleo win from user input", func(t *testing.T) {
-        in := strings.NewReader("5\nCleo wins\n")
+        in := strings.NewReader("Chris, Cleo, Ruth, Tiest, Apollo\nCleo wins\n")
         game := &GameSpy{}
 
-        cli := NewCLI(in, dummyStdOut, game)
+        cli := NewCLI(in, dummyStdOut, game, dummyStore)
         cli.PlayPoker()
 
         if game.FinishedWith != "Cleo" {
@@ -35,68 +40,292 @@ func TestCLI(t *testing.T) {
         }
     })
 
+    t.Run("record the finishing order from user input", func(t *testing.T) {
+        in := strings.NewReader("Chris, Cleo, Ruth, Tiest, Apollo\nCleo wins, Chris, Ruth\n")
+        game := &GameSpy{}
+
+        cli := NewCLI(in, dummyStdOut, game, dummyStore)
+        cli.PlayPoker()
+
+        if game.FinishedWith != "Cleo" {
+            t.Errorf("wanted Finish with Cleo but got %v", game.FinishedWith)
+        }
+
+        if !reflect.DeepEqual(game.FinishedPlacings, []string{"Chris", "Ruth"}) {
+            t.Errorf("wanted Chris then Ruth placed but got %v", game.FinishedPlacings)
+        }
+    })
+
     t.Run("it schedules printing of blind values", func(t *testing.T) {
-        in := strings.NewReader("5\nChris wins\n")
+        in := strings.NewReader("Chris, Cleo, Ruth, Tiest, Apollo\nChris wins\n")
         playerStore := &StubPlayerStore{}
         blindAlerter := &SpyBlindAlerter{}
 
         game := NewTexasHoldem(blindAlerter, playerStore)
 
-        cli := NewCLI(in, dummyStdOut, game)
+        cli := NewCLI(in, dummyStdOut, game, dummyStore)
         cli.PlayPoker()
 
         dest := os.Stdout
 
         cases := []ScheduledAlert{
-            {0 * time.Second, 100, dest},
-            {10 * time.Minute, 200, dest},
-            {20 * time.Minute, 300, dest},
-            {30 * time.Minute, 400, dest},
-            {40 * time.Minute, 500, dest},
-            {50 * time.Minute, 600, dest},
-            {60 * time.Minute, 800, dest},
-            {70 * time.Minute, 1000, dest},
-            {80 * time.Minute, 2000, dest},
-            {90 * time.Minute, 4000, dest},
-            {100 * time.Minute, 8000, dest},
+            {At: 0 * time.Second, Amount: 100, To: dest},
+            {At: 10 * time.Minute, Amount: 200, To: dest},
+            {At: 20 * time.Minute, Amount: 300, To: dest},
+            {At: 30 * time.Minute, Amount: 400, To: dest},
+            {At: 40 * time.Minute, Amount: 500, To: dest},
+            {At: 50 * time.Minute, Amount: 600, To: dest},
+            {At: 60 * time.Minute, Amount: 800, To: dest},
+            {At: 70 * time.Minute, Amount: 1000, To: dest},
+            {At: 80 * time.Minute, Amount: 2000, To: dest},
+            {At: 90 * time.Minute, Amount: 4000, To: dest},
+            {At: 100 * time.Minute, Amount: 8000, To: dest},
         }
 
         CheckSchedulingCases(t, cases, blindAlerter)
     })
 
-    t.Run("it prompts the user to enter the number of players and starts the game", func(t *testing.T) {
+    t.Run("it starts the game with the chosen blind structure", func(t *testing.T) {
+        in := strings.NewReader("Chris, Cleo, Ruth, Tiest, Apollo\nChris wins\n")
+        game := &GameSpy{}
+
+        cli := NewCLI(in, dummyStdOut, game, dummyStore)
+        cli.UseBlindStructure(TurboBlindStructure)
+        cli.PlayPoker()
+
+        if game.StartedWithBlinds.Name != TurboBlindStructure.Name {
+            t.Errorf("wanted Start called with %q blinds but got %q", TurboBlindStructure.Name, game.StartedWithBlinds.Name)
+        }
+    })
+
+    t.Run("it controls the blind clock until a winner is declared", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("Chris, Cleo, Ruth, Tiest, Apollo\npause\nnext\nresume\nChris wins\n")
+        game := &GameSpy{Clock: ClockState{Level: 2, SmallBlind: 200, BigBlind: 400, Remaining: 10 * time.Minute}}
+
+        cli := NewCLI(in, stdout, game, dummyStore)
+        cli.PlayPoker()
+
+        wantCommands := []ClockCommand{PauseClock, AdvanceClock, ResumeClock}
+        if !reflect.DeepEqual(game.ClockCommands, wantCommands) {
+            t.Errorf("got clock commands %v, want %v", game.ClockCommands, wantCommands)
+        }
+
+        if game.FinishedWith != "Chris" {
+            t.Errorf("wanted Finish with Chris but got %v", game.FinishedWith)
+        }
+
+        state := game.Clock.String() + "\n"
+        assertMessagesSentToUser(t, stdout, PlayerPrompt, state, state, state)
+    })
+
+    t.Run("it prompts the user to enter the names of the players and starts the game", func(t *testing.T) {
         stdout := &bytes.Buffer{}
-        in := strings.NewReader("7\n")
+        in := strings.NewReader("Chris, Cleo, Ruth\n")
         game := &GameSpy{}
 
-        cli := NewCLI(in, stdout, game)
+        cli := NewCLI(in, stdout, game, dummyStore)
         cli.PlayPoker()
 
         wantPrompt := PlayerPrompt
 
         assertMessagesSentToUser(t, stdout, wantPrompt)
 
-        if game.StartedWith != 7 {
-            t.Errorf("wanted Start called with 7 but got %d", game.StartedWith)
+        want := []string{"Chris", "Cleo", "Ruth"}
+        if !reflect.DeepEqual(game.StartedWithPlayers, want) {
+            t.Errorf("wanted Start called with %v but got %v", want, game.StartedWithPlayers)
         }
     })
 
-    t.Run("it prints an error when a non numeric value is entered and does not start the game", func(t *testing.T) {
+    t.Run("it prints an error when too few players are entered and does not start the game", func(t *testing.T) {
         stdout := &bytes.Buffer{}
         in := strings.NewReader("Pies\n")
         game := &GameSpy{}
 
-        cli := NewCLI(in, stdout, game)
+        cli := NewCLI(in, stdout, game, dummyStore)
         cli.PlayPoker()
 
-        wantPrompt := PlayerPrompt + BadPlayerInputErrMsg
+        _, err := ParseRoster("Pies", DefaultMinPlayers, DefaultMaxPlayers)
 
-        assertMessagesSentToUser(t, stdout, wantPrompt)
+        assertMessagesSentToUser(t, stdout, PlayerPrompt, err.Error()+"\n", PlayerPrompt)
 
         if game.StartCalled {
             t.Errorf("game should not have started")
         }
     })
+
+    t.Run("it asks again until it gets a good roster of players", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("Pies\nChris, Chris\nChris, , Ruth\nChris, Cleo, Ruth\nChris wins\n")
+        game := &GameSpy{}
+
+        cli := NewCLI(in, stdout, game, dummyStore)
+        cli.PlayPoker()
+
+        if got := strings.Count(stdout.String(), PlayerPrompt); got != 4 {
+            t.Errorf("got asked for the players %d times, want 4", got)
+        }
+
+        if game.StartedWith != 3 {
+            t.Errorf("wanted Start called with 3 players but got %d", game.StartedWith)
+        }
+    })
+
+    t.Run("it uses the player range it is given", func(t *testing.T) {
+        in := strings.NewReader("A, B, C, D, E, F, G, H, I, J, K, Chris\nChris wins\n")
+        game := &GameSpy{}
+
+        cli := NewCLI(in, dummyStdOut, game, dummyStore)
+        cli.UsePlayerRange(2, 22)
+        cli.PlayPoker()
+
+        if game.StartedWith != 12 {
+            t.Errorf("wanted Start called with 12 players but got %d", game.StartedWith)
+        }
+    })
+
+    t.Run("it asks again for the winner when the declaration is no good or names someone not playing", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("Chris, Cleo, Ruth\nChris win\nwins\nChris wins, Cleo, Ruth, Tiest\nChris wins\n")
+        game := &GameSpy{}
+
+        cli := NewCLI(in, stdout, game, dummyStore)
+        cli.PlayPoker()
+
+        if game.FinishedWith != "Chris" {
+            t.Errorf("wanted Finish with Chris but got %v", game.FinishedWith)
+        }
+
+        if got := strings.Count(stdout.String(), "is no good"); got != 3 {
+            t.Errorf("got %d errors reported, want 3 in %q", got, stdout.String())
+        }
+    })
+}
+
+func TestCLI_Run(t *testing.T) {
+    t.Run("plays games until the user quits", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("new game\nChris, Cleo, Ruth, Tiest, Apollo\nChris wins\nnew game\nChris, Cleo, Ruth\nCleo wins, Chris\nquit\nnew game\n")
+        store := NewInMemoryPlayerStore()
+        game := NewTexasHoldem(&SpyBlindAlerter{}, store)
+
+        cli := NewCLI(in, stdout, game, store)
+        cli.Run()
+
+        assertScoreEquals(t, store.GetPlayerScore("Chris"), 1)
+        assertScoreEquals(t, store.GetPlayerScore("Cleo"), 1)
+        assertMessagesSentToUser(t, stdout, PlayerPrompt, PlayerPrompt, GoodbyeMsg)
+    })
+
+    t.Run("shows the league and players' scores", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("league\nscore Chris\nscore Apollo\n")
+        store := NewInMemoryPlayerStore(Player{"Cleo", 32, 0}, Player{"Chris", 20, 0})
+
+        cli := NewCLI(in, stdout, &GameSpy{}, store)
+        cli.Run()
+
+        assertMessagesSentToUser(t, stdout,
+            "1. Cleo 32\n",
+            "2. Chris 20\n",
+            "Chris has 20 wins\n",
+            "Apollo has 0 wins\n",
+        )
+    })
+
+    t.Run("stops the running game's alerts when the input ends", func(t *testing.T) {
+        in := strings.NewReader("new game\nChris, Cleo, Ruth, Tiest, Apollo\n")
+        alerter := &SpyBlindAlerter{}
+        game := NewTexasHoldem(alerter, NewInMemoryPlayerStore())
+
+        cli := NewCLI(in, &bytes.Buffer{}, game, NewInMemoryPlayerStore())
+        cli.Run()
+
+        assertCancelled(t, alerter.ctx)
+    })
+
+    t.Run("tells the user when there's no game to finish or one is already running", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("Chris wins\nnew game\nChris, Cleo, Ruth, Tiest, Apollo\nnew game\n")
+        game := &GameSpy{}
+
+        cli := NewCLI(in, stdout, game, NewInMemoryPlayerStore())
+        cli.Run()
+
+        assertMessagesSentToUser(t, stdout, NoGameRunningMsg, PlayerPrompt, GameRunningMsg)
+
+        if game.FinishedCalled {
+            t.Error("game should not have finished")
+        }
+    })
+
+    t.Run("keeps the game running to declare the winner again when the result can't be recorded", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("new game\nChris, Cleo\nChris wins\nChris wins\nquit\n")
+        store := &failOnceStore{InMemoryPlayerStore: NewInMemoryPlayerStore()}
+        game := NewTexasHoldem(&SpyBlindAlerter{}, store)
+
+        cli := NewCLI(in, stdout, game, store)
+        cli.Run()
+
+        assertMessagesSentToUser(t, stdout, PlayerPrompt, "disk full\n", GoodbyeMsg)
+        assertScoreEquals(t, store.GetPlayerScore("Chris"), 1)
+    })
+
+    t.Run("starts each game with the blind structure it names", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("new game turbo\nChris, Cleo\nChris wins\nnew game\nChris, Cleo\nChris wins\nnew game hyper\n")
+        game := &GameSpy{}
+        var started []string
+
+        cli := NewCLI(in, stdout, &blindsRecorder{game, &started}, NewInMemoryPlayerStore())
+        cli.Run()
+
+        if !reflect.DeepEqual(started, []string{TurboBlindStructure.Name, DefaultBlindStructure.Name}) {
+            t.Errorf("got games started with %v blinds, want turbo then the default", started)
+        }
+
+        if !strings.Contains(stdout.String(), "hyper") {
+            t.Errorf("got %q, want an error about the unknown hyper blinds", stdout.String())
+        }
+    })
+
+    t.Run("helps with unknown commands", func(t *testing.T) {
+        stdout := &bytes.Buffer{}
+        in := strings.NewReader("deal\nhelp\n")
+
+        cli := NewCLI(in, stdout, &GameSpy{}, NewInMemoryPlayerStore())
+        cli.Run()
+
+        assertMessagesSentToUser(t, stdout, UnknownCommandMsg, SessionHelp)
+    })
+}
+
+// blindsRecorder is a Game that records the name of the blind structure
+// each game is started with.
+type blindsRecorder struct {
+    *GameSpy
+    started *[]string
+}
+
+func (b *blindsRecorder) Start(ctx context.Context, players []string, blinds BlindStructure, out io.Writer) {
+    *b.started = append(*b.started, blinds.Name)
+    b.GameSpy.Start(ctx, players, blinds, out)
+}
+
+// failOnceStore is an InMemoryPlayerStore that fails to record the first
+// result it is given.
+type failOnceStore struct {
+    *InMemoryPlayerStore
+    failed bool
+}
+
+func (s *failOnceStore) RecordResult(game GameRecord) (GameRecord, error) {
+    if !s.failed {
+        s.failed = true
+        return GameRecord{}, errors.New("disk full")
+    }
+    return s.InMemoryPlayerStore.RecordResult(game)
 }
 
 func assertMessagesSentToUser(t testing.TB, stdout *bytes.Buffer, messages ...string) {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -11,7 +12,19 @@ import (
 const dbFileName = "game.db.json"
 
 func main() {
-    store, close, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
+    storeFlag := flag.String("store", dbFileName, "player store to use, json:path, sqlite:path, memory or memory:path")
+    blindsFlag := flag.String("blinds", poker.DefaultBlindStructure.Name, "blind structure preset name, or path to a JSON or YAML blind structure file")
+    minPlayers := flag.Int("min-players", poker.DefaultMinPlayers, "fewest players a game can have")
+    maxPlayers := flag.Int("max-players", poker.DefaultMaxPlayers, "most players a game can have")
+    flag.Parse()
+
+    blinds, err := poker.LookupBlindStructure(*blindsFlag)
+
+    if err != nil {
+        log.Fatal(err)
+    }
+
+    store, close, err := poker.OpenPlayerStore(*storeFlag)
 
     if err != nil {
         log.Fatal(err)
@@ -19,10 +32,12 @@ func main() {
     defer close()
     
     fmt.Println("Let's play poker")
-    fmt.Println("Type {Name} wins to record a win")
+    fmt.Println("Type new game to start a game, or help to see every command")
 
     game := poker.NewTexasHoldem(poker.BlindAlerterFunc(poker.Alerter), store)
 
-    cli := poker.NewCLI(os.Stdin, os.Stdout, game)
-    cli.PlayPoker()
-}
\ No newline at end of file
+    cli := poker.NewCLI(os.Stdin, os.Stdout, game, store)
+    cli.UseBlindStructure(blinds)
+    cli.UsePlayerRange(*minPlayers, *maxPlayers)
+    cli.Run()
+}
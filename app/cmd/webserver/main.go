@@ -1,8 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	poker "learn-go-with-tests/app"
 )
@@ -10,24 +18,104 @@ import (
 const dbFileName = "game.db.json"
 
 func main() {
-    store, close, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
+    storeFlag := flag.String("store", dbFileName, "player store to use, json:path, sqlite:path, memory or memory:path")
+    blindsFlag := flag.String("blinds", "", "comma separated paths to JSON or YAML blind structure files to offer alongside the presets")
+    seasonsFlag := flag.String("seasons", "", "path to a JSON or YAML file of named seasons to offer alongside the quarters, e.g. 2026-Q3")
+    tiesFlag := flag.String("ties", string(poker.TieBreakJoined), "how to order players level on wins in the league: joined, name, last-win or head-to-head")
+    authFlag := flag.String("auth", "", "path to a JSON or YAML file of API tokens and HMAC keys needed to record wins and run games, open to anyone if empty")
+    flag.Parse()
+
+    store, close, err := poker.OpenPlayerStore(*storeFlag)
 
     if err != nil {
         log.Fatal(err)
     }
     defer close()
     
-    game := poker.NewTexasHoldem(poker.BlindAlerterFunc(poker.Alerter), store)
-    server, err := poker.NewPlayerServer(store, game)
+    newGame := func() poker.Game {
+        return poker.NewTexasHoldem(poker.BlindAlerterFunc(poker.Alerter), store)
+    }
+    server, err := poker.NewPlayerServer(store, newGame)
 
     if err != nil {
         log.Fatal("problem creating player server", err)
     }
 
-    if err := http.ListenAndServe(":5000", server); err != nil {
+    for _, path := range strings.FieldsFunc(*blindsFlag, func(r rune) bool { return r == ',' }) {
+        blinds, err := poker.BlindStructureFromFile(path)
+
+        if err != nil {
+            log.Fatal(err)
+        }
+
+        server.RegisterBlindStructure(blinds)
+    }
+
+    tieBreak, err := poker.ParseTieBreak(*tiesFlag)
+
+    if err != nil {
+        log.Fatal(err)
+    }
+
+    server.BreakTiesBy(tieBreak)
+
+    if *seasonsFlag != "" {
+        seasons, err := poker.SeasonsFromFile(*seasonsFlag)
+
+        if err != nil {
+            log.Fatal(err)
+        }
+
+        for _, season := range seasons {
+            server.RegisterSeason(season)
+        }
+    }
+
+    var handler http.Handler = server
+
+    if *authFlag != "" {
+        config, err := poker.AuthConfigFromFile(*authFlag)
+
+        if err != nil {
+            log.Fatal(err)
+        }
+
+        auth, err := poker.NewAuthenticator(config)
+
+        if err != nil {
+            log.Fatal(err)
+        }
+
+        handler = auth.Protect(server)
+    }
+
+    // stop cleanly on Ctrl-C or SIGTERM so that close saves the store, a
+    // memory:path store only writes its snapshot then
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+
+    httpServer := &http.Server{Addr: ":5000", Handler: handler}
+    served := make(chan error, 1)
+
+    go func() {
+        served <- httpServer.ListenAndServe()
+    }()
+
+    select {
+    case err := <-served:
+        close()
         log.Fatalf("could not listen on port 5000 %v", err)
+    case <-ctx.Done():
+    }
+
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+        log.Printf("problem shutting down, %v", err)
     }
 }
 
-// curl -X POST http://localhost:5000/players/Pepper
-// curl http://localhost:5000/players/Pepper
\ No newline at end of file
+// curl -X POST -H "Authorization: Bearer <token>" http://localhost:5000/players/Pepper
+// curl http://localhost:5000/players/Pepper
+// curl http://localhost:5000/league?season=2026-Q3
@@ -4,23 +4,55 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"sort"
+	"sync"
+	"time"
 )
 
 
+// FileSystemPlayerStore keeps the league and game results in a JSON file. It
+// is safe to use from multiple goroutines.
 type FileSystemPlayerStore struct {
+    mu sync.RWMutex
     database *json.Encoder
     league League
+    games []GameRecord
+    wins []Win
+    ratings Ratings
+    now func() time.Time
 }
 
-func (f *FileSystemPlayerStore) GetLeague() League {
-    sort.Slice(f.league, func(i, j int) bool {
-        return f.league[i].Wins > f.league[j].Wins
-    })
-    return f.league
+// GetLeague returns a copy of the part of the league asked for, ranked.
+func (f *FileSystemPlayerStore) GetLeague(query LeagueQuery) Standings {
+    f.mu.RLock()
+    defer f.mu.RUnlock()
+
+    league := f.league
+    if !query.Window.IsAllTime() {
+        league = league.in(query.Window, f.wins, f.games)
+    }
+    return league.query(query, f.wins, f.games)
+}
+
+// GetLeagueIn returns the league counting only the wins and games in window,
+// ranked by wins.
+func (f *FileSystemPlayerStore) GetLeagueIn(window Window) League {
+    return f.GetLeague(LeagueQuery{Window: window}).Players()
+}
+
+func (f *FileSystemPlayerStore) GetPlayer(name string) (Player, bool) {
+    f.mu.RLock()
+    defer f.mu.RUnlock()
+
+    if player := f.league.Find(name); player != nil {
+        return *player, true
+    }
+    return Player{}, false
 }
 
 func (f *FileSystemPlayerStore) GetPlayerScore(name string) int {
+    f.mu.RLock()
+    defer f.mu.RUnlock()
+
 	player := f.league.Find(name)
 
     if player != nil {
@@ -30,16 +62,157 @@ func (f *FileSystemPlayerStore) GetPlayerScore(name string) int {
     return 0
 }
 
-func (f *FileSystemPlayerStore) RecordWin(name string) {
-    player := f.league.Find(name)
+// RecordWin adds a win to the player's score. If the win can't be saved the
+// store is left as it was.
+func (f *FileSystemPlayerStore) RecordWin(name string) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
 
-    if player != nil {
-        player.Wins++
-    } else {
-        f.league = append(f.league, Player{name, 1})
+    league := f.league.withWin(name)
+    wins := append(append([]Win{}, f.wins...), Win{name, f.now()})
+
+    if err := f.save(database{league, f.games, wins, f.ratings}); err != nil {
+        return fmt.Errorf("problem recording win for %s, %v", name, err)
+    }
+
+    f.league = league
+    f.wins = wins
+    return nil
+}
+
+// RecordGame stores the result of a finished game, giving it an ID if it
+// doesn't have one, and adds a game played for everyone who played in it.
+func (f *FileSystemPlayerStore) RecordGame(game GameRecord) (GameRecord, error) {
+    return f.recordGame(game, false)
+}
+
+// RecordResult records a finished game as RecordGame does and a win for its
+// winner as RecordWin does, in one write, so either both are saved or
+// neither is.
+func (f *FileSystemPlayerStore) RecordResult(game GameRecord) (GameRecord, error) {
+    return f.recordGame(game, true)
+}
+
+func (f *FileSystemPlayerStore) recordGame(game GameRecord, withWin bool) (GameRecord, error) {
+    if game.ID == "" {
+        game.ID = newGameID()
+    }
+
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    league, wins := f.league, f.wins
+
+    if winner := game.Winner(); withWin && winner != "" {
+        league = league.withWin(winner)
+        wins = append(append([]Win{}, wins...), Win{winner, f.now()})
+    }
+
+    games := append(append([]GameRecord{}, f.games...), game)
+    league = league.withGamePlayed(gamePlayers(game))
+    ratings := f.ratings.with(game)
+
+    if err := f.save(database{league, games, wins, ratings}); err != nil {
+        return GameRecord{}, fmt.Errorf("problem recording game %s, %v", game.ID, err)
+    }
+
+    f.league = league
+    f.games = games
+    f.wins = wins
+    f.ratings = ratings
+    return game, nil
+}
+
+// GetRatings returns a copy of everyone's ratings.
+func (f *FileSystemPlayerStore) GetRatings() Ratings {
+    f.mu.RLock()
+    defer f.mu.RUnlock()
+
+    return f.ratings.copy()
+}
+
+// GetGames returns a copy of every game recorded, oldest first.
+func (f *FileSystemPlayerStore) GetGames() []GameRecord {
+    f.mu.RLock()
+    defer f.mu.RUnlock()
+
+    return append([]GameRecord{}, f.games...)
+}
+
+func (f *FileSystemPlayerStore) GetGame(id string) (GameRecord, bool) {
+    f.mu.RLock()
+    defer f.mu.RUnlock()
+
+    for _, game := range f.games {
+        if game.ID == id {
+            return game, true
+        }
+    }
+    return GameRecord{}, false
+}
+
+// RenamePlayer renames the player named from to, in the league and in the
+// games they played.
+func (f *FileSystemPlayerStore) RenamePlayer(from, to string) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    league, err := f.league.renamed(from, to)
+    if err != nil {
+        return err
+    }
+
+    games, _ := renamedInGames(f.games, from, to)
+
+    return f.update(database{league, games, renamedInWins(f.wins, from, to), RateGames(games)}, fmt.Sprintf("renaming %s", from))
+}
+
+// MergePlayers adds the wins and games of the player named from to the
+// player named into, and removes from.
+func (f *FileSystemPlayerStore) MergePlayers(from, into string) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    games, shared := renamedInGames(f.games, from, into)
+
+    league, err := f.league.merged(from, into, shared)
+    if err != nil {
+        return err
+    }
+
+    return f.update(database{league, games, renamedInWins(f.wins, from, into), RateGames(games)}, fmt.Sprintf("merging %s into %s", from, into))
+}
+
+// DeletePlayer removes the player from the league. The games they played are
+// kept as they were.
+func (f *FileSystemPlayerStore) DeletePlayer(name string) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    league, err := f.league.without(name)
+    if err != nil {
+        return err
+    }
+
+    return f.update(database{league, f.games, winsWithout(f.wins, name), f.ratings}, fmt.Sprintf("deleting %s", name))
+}
+
+// update saves db and makes it the store's. The caller must hold f.mu.
+func (f *FileSystemPlayerStore) update(db database, doing string) error {
+    if err := f.save(db); err != nil {
+        return fmt.Errorf("problem %s, %v", doing, err)
     }
 
-    f.database.Encode(f.league)
+    f.league = db.League
+    f.games = db.Games
+    f.wins = db.Wins
+    f.ratings = db.Ratings
+    return nil
+}
+
+// save writes db to the file. The caller must hold f.mu.
+func (f *FileSystemPlayerStore) save(db database) error {
+    return f.database.Encode(db)
 }
 
 func NewFileSystemPlayerStore(file *os.File) (*FileSystemPlayerStore, error) {
@@ -49,15 +222,19 @@ func NewFileSystemPlayerStore(file *os.File) (*FileSystemPlayerStore, error) {
         return nil, fmt.Errorf("problem initialising player db file, %v", err)
     }
 
-    league, err := NewLeague(file)
+    db, err := newDatabase(file)
 
     if err != nil {
         return nil, fmt.Errorf("problem loading player store from file %s, %v", file.Name(), err)
     }
 
     return &FileSystemPlayerStore{
-        database: json.NewEncoder(&tape{file}),
-        league:   league,
+        database: json.NewEncoder(newTape(file.Name())),
+        league:   db.League,
+        games:    db.Games,
+        wins:     db.Wins,
+        ratings:  db.Ratings,
+        now:      time.Now,
     }, nil
 }
 
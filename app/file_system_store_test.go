@@ -1,9 +1,11 @@
 package poker
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestFileSystemStore(t *testing.T) {
@@ -18,17 +20,17 @@ func TestFileSystemStore(t *testing.T) {
 
         assertNoError(t, err)
 
-        got := store.GetLeague()
+        got := store.GetLeague(WholeLeague).Players()
 
         want := []Player{
-            {"Chris", 33},
-            {"Cleo", 10},
+            {"Chris", 33, 0},
+            {"Cleo", 10, 0},
         }
 
         assertLeague(t, got, want)
 
         // read again
-        got = store.GetLeague()
+        got = store.GetLeague(WholeLeague).Players()
         assertLeague(t, got, want)
     })
 	
@@ -81,6 +83,89 @@ func TestFileSystemStore(t *testing.T) {
 		assertScoreEquals(t, got, want)
 	})
 
+    t.Run("records games and reads them back", func(t *testing.T) {
+        database, cleanDatabase := createTempFile(t, `[
+            {"Name": "Cleo", "Wins": 10}]`)
+        defer cleanDatabase()
+
+        store, err := NewFileSystemPlayerStore(database)
+
+        assertNoError(t, err)
+
+        played := GameRecord{
+            StartedAt:       time.Date(2021, 2, 18, 20, 0, 0, 0, time.UTC),
+            FinishedAt:      time.Date(2021, 2, 18, 22, 0, 0, 0, time.UTC),
+            NumberOfPlayers: 3,
+            Participants:    []string{"Cleo", "Chris"},
+            FinishingOrder:  []string{"Cleo", "Chris"},
+            BlindStructure:  "default",
+        }
+
+        recorded, err := store.RecordGame(played)
+        assertNoError(t, err)
+
+        if recorded.ID == "" {
+            t.Fatal("expected the store to give the game an ID")
+        }
+
+        played.ID = recorded.ID
+
+        reopened := reopenFileSystemStore(t, database)
+
+        got, found := reopened.GetGame(played.ID)
+
+        if !found {
+            t.Fatalf("game %s not found after reopening the store", played.ID)
+        }
+
+        assertGameRecord(t, got, played)
+        assertLeague(t, reopened.GetLeague(WholeLeague).Players(), []Player{{"Cleo", 10, 1}, {"Chris", 0, 1}})
+
+        if len(reopened.GetGames()) != 1 {
+            t.Errorf("got %d games, want 1", len(reopened.GetGames()))
+        }
+    })
+
+    t.Run("keeps the previous league when saving a win fails", func(t *testing.T) {
+        database, cleanDatabase := createTempFile(t, `[
+            {"Name": "Cleo", "Wins": 10},
+            {"Name": "Chris", "Wins": 33}]`)
+        defer cleanDatabase()
+
+        store, err := NewFileSystemPlayerStore(database)
+        assertNoError(t, err)
+
+        store.database = json.NewEncoder(&tape{database.Name(), failingTempFile(t)})
+
+        err = store.RecordWin("Chris")
+
+        if err == nil {
+            t.Fatal("expected an error but didn't get one")
+        }
+
+        want := []Player{
+            {"Chris", 33, 0},
+            {"Cleo", 10, 0},
+        }
+
+        assertLeague(t, store.GetLeague(WholeLeague).Players(), want)
+        assertLeague(t, reopenFileSystemStore(t, database).GetLeague(WholeLeague).Players(), want)
+    })
+
+    t.Run("rates the games of files written before ratings were kept", func(t *testing.T) {
+        database, cleanDatabase := createTempFile(t, `{
+            "League": [{"Name": "Cleo", "Wins": 1, "Played": 1}, {"Name": "Chris", "Wins": 0, "Played": 1}],
+            "Games": [{"ID": "abc", "Participants": ["Cleo", "Chris"], "FinishingOrder": ["Cleo", "Chris"]}]}`)
+        defer cleanDatabase()
+
+        store, err := NewFileSystemPlayerStore(database)
+        assertNoError(t, err)
+
+        if got := store.GetRatings().Get("Cleo"); len(got.History) != 1 || got.Rating <= InitialRating {
+            t.Errorf("got Cleo rated %+v, want Cleo's rating up after winning game abc", got)
+        }
+    })
+
     t.Run("works with an empty file", func(t *testing.T) {
         database, cleanDatabase := createTempFile(t, "")
         defer cleanDatabase()
@@ -92,6 +177,23 @@ func TestFileSystemStore(t *testing.T) {
 	
 }
 
+// reopenFileSystemStore opens a new store on the file database was created
+// from, as writes replace the file the database handle was opened on.
+func reopenFileSystemStore(t testing.TB, database *os.File) *FileSystemPlayerStore {
+    t.Helper()
+
+    file, err := os.OpenFile(database.Name(), os.O_RDWR, 0666)
+    if err != nil {
+        t.Fatalf("could not reopen %s %v", database.Name(), err)
+    }
+    t.Cleanup(func() { file.Close() })
+
+    store, err := NewFileSystemPlayerStore(file)
+    assertNoError(t, err)
+
+    return store
+}
+
 func assertScoreEquals(t testing.TB, got, want int) {
     t.Helper()
     if got != want {
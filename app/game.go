@@ -1,39 +1,165 @@
 package poker
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"sync"
 	"time"
 )
 
 
+// Game runs a single game of poker between the players on its roster. Start
+// runs a blind clock for the game's blind structure; its alerts are cancelled
+// when ctx is done or when Finish is called. While the game runs its clock
+// can be paused, resumed, advanced and rewound with ControlClock. Finish
+// takes the winner followed by the places of any other players, best first,
+// all of whom must be on the roster. ID is the running game's ID, which its
+// GameRecord is saved under, or "" when no game is running.
 type Game interface {
-    Start(numberOfPlayers int, alertsDestination io.Writer)
-    Finish(winner string)
+    Start(ctx context.Context, players []string, blinds BlindStructure, alertsDestination io.Writer)
+    Finish(winner string, placings ...string) error
+    ControlClock(cmd ClockCommand) (ClockState, error)
+    ClockState() (ClockState, error)
+    ID() string
 }
 
 type TexasHoldem struct {
 	alerter BlindAlerter
 	store   PlayerStore
+
+	now     func() time.Time
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	clock   *BlindClock
+	running GameRecord
 }
 
-func (p *TexasHoldem) Start(numberOfPlayers int, alertsDestination io.Writer) {
-	blindIncrement := time.Duration(5+numberOfPlayers) * time.Minute
+// Start plays the game with blinds, or with DefaultBlindStructure if blinds
+// has no levels. The length of the blind levels depends on how many players
+// there are.
+func (p *TexasHoldem) Start(ctx context.Context, players []string, blinds BlindStructure, alertsDestination io.Writer) {
+	if len(blinds.Levels) == 0 {
+		blinds = DefaultBlindStructure
+	}
 
-	blinds := []int{100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000}
-	blindTime := 0 * time.Second
-	for _, blind := range blinds {
-		p.alerter.ScheduleAlertAt(blindTime, blind, alertsDestination)
-		blindTime = blindTime + blindIncrement
+	ctx, cancel := context.WithCancel(ctx)
+	clock := NewBlindClock(ctx, p.alerter, blinds, len(players), alertsDestination)
+
+	p.mu.Lock()
+	p.stop()
+	p.cancel = cancel
+	p.clock = clock
+	p.running = GameRecord{
+		ID:              newGameID(),
+		StartedAt:       p.now(),
+		NumberOfPlayers: len(players),
+		Participants:    append([]string{}, players...),
+		BlindStructure:  blinds.Name,
 	}
+	p.mu.Unlock()
+
+	clock.Start()
 }
 
-func (p *TexasHoldem) Finish(winner string) {
-	p.store.RecordWin(winner)
+// Finish records the winner's win and the result of the running game, in one
+// write to the store, and stops the game. It fails without recording anything
+// if the winner or anyone placed isn't on the game's roster, or is placed
+// twice, and a game whose result couldn't be recorded keeps running so it can
+// be finished again.
+func (p *TexasHoldem) Finish(winner string, placings ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.clock == nil {
+		return ErrNoGameRunning
+	}
+
+	finishingOrder := append([]string{winner}, placings...)
+
+	if err := checkFinishingOrder(finishingOrder, p.running.Participants); err != nil {
+		return err
+	}
+
+	record := p.running
+	record.FinishedAt = p.now()
+	record.FinishingOrder = finishingOrder
+
+	if _, err := p.store.RecordResult(record); err != nil {
+		return err
+	}
+
+	p.stop()
+	return nil
+}
+
+// checkFinishingOrder makes sure everyone placed is on the roster, once.
+func checkFinishingOrder(finishingOrder, roster []string) error {
+	placed := map[string]bool{}
+
+	for _, name := range finishingOrder {
+		if !containsName(roster, name) {
+			return fmt.Errorf("%w %q", ErrUnknownPlayer, name)
+		}
+		if placed[name] {
+			return fmt.Errorf("%w %q", ErrDuplicatePlayer, name)
+		}
+		placed[name] = true
+	}
+
+	return nil
+}
+
+// ControlClock pauses, resumes, advances or rewinds the running game's blinds.
+func (p *TexasHoldem) ControlClock(cmd ClockCommand) (ClockState, error) {
+	clock, err := p.runningClock()
+	if err != nil {
+		return ClockState{}, err
+	}
+	return clock.Control(cmd)
+}
+
+// ClockState returns the running game's blind level and the time left in it.
+func (p *TexasHoldem) ClockState() (ClockState, error) {
+	clock, err := p.runningClock()
+	if err != nil {
+		return ClockState{}, err
+	}
+	return clock.State(), nil
+}
+
+func (p *TexasHoldem) ID() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running.ID
+}
+
+func (p *TexasHoldem) runningClock() (*BlindClock, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.clock == nil {
+		return nil, ErrNoGameRunning
+	}
+	return p.clock, nil
+}
+
+// stop cancels the alerts of the running game, if there is one. The caller
+// must hold p.mu.
+func (p *TexasHoldem) stop() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	p.clock = nil
+	p.running = GameRecord{}
 }
 
 func NewTexasHoldem(alerter BlindAlerter, store PlayerStore) *TexasHoldem {
     return &TexasHoldem{
         alerter:alerter,
         store:store,
+        now: time.Now,
     }
-}
\ No newline at end of file
+}
@@ -0,0 +1,103 @@
+package poker
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// GameRecord is the result of a finished game.
+type GameRecord struct {
+    ID              string
+    StartedAt       time.Time
+    FinishedAt      time.Time
+    NumberOfPlayers int
+    Participants    []string
+    FinishingOrder  []string
+    BlindStructure  string
+}
+
+// Winner is the player who finished first, or "" if nobody did.
+func (g GameRecord) Winner() string {
+    if len(g.FinishingOrder) == 0 {
+        return ""
+    }
+    return g.FinishingOrder[0]
+}
+
+// gamePlayers is everyone who played in game: the participants followed by
+// anyone placed who isn't one of them.
+func gamePlayers(game GameRecord) []string {
+    players := append([]string{}, game.Participants...)
+
+    for _, name := range game.FinishingOrder {
+        if !containsName(players, name) {
+            players = append(players, name)
+        }
+    }
+
+    return players
+}
+
+// withPlayerRenamed returns a copy of the game with the player named from
+// renamed to. If to also played, they are listed once, where they first were.
+func (g GameRecord) withPlayerRenamed(from, to string) GameRecord {
+    g.Participants = renameIn(g.Participants, from, to)
+    g.FinishingOrder = renameIn(g.FinishingOrder, from, to)
+    return g
+}
+
+func renameIn(names []string, from, to string) []string {
+    if names == nil {
+        return nil
+    }
+
+    renamed := []string{}
+
+    for _, name := range names {
+        if name == from {
+            name = to
+        }
+        if !containsName(renamed, name) {
+            renamed = append(renamed, name)
+        }
+    }
+
+    return renamed
+}
+
+// renamedInGames returns a copy of games with the player named from renamed
+// to, and how many of the games they both played in.
+func renamedInGames(games []GameRecord, from, to string) (renamed []GameRecord, shared int) {
+    renamed = make([]GameRecord, 0, len(games))
+
+    for _, game := range games {
+        players := gamePlayers(game)
+
+        if containsName(players, from) {
+            if containsName(players, to) {
+                shared++
+            }
+            game = game.withPlayerRenamed(from, to)
+        }
+
+        renamed = append(renamed, game)
+    }
+
+    return renamed, shared
+}
+
+func containsName(names []string, name string) bool {
+    for _, n := range names {
+        if n == name {
+            return true
+        }
+    }
+    return false
+}
+
+func newGameID() string {
+    id := make([]byte, 8)
+    rand.Read(id)
+    return hex.EncodeToString(id)
+}
@@ -0,0 +1,126 @@
+package poker
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ActiveGame describes a game that is being played right now.
+type ActiveGame struct {
+    ID             string
+    Players        []string
+    BlindStructure string
+    StartedAt      time.Time
+    Clock          ClockState
+}
+
+type registeredGame struct {
+    game Game
+    info ActiveGame
+}
+
+func (r registeredGame) describe() ActiveGame {
+    info := r.info
+    info.Clock, _ = r.game.ClockState()
+    return info
+}
+
+// GameRegistry runs a game for each table playing at once. Every game is
+// made by newGame and is looked up by the ID it has once it has started.
+type GameRegistry struct {
+    newGame func() Game
+    now     func() time.Time
+
+    mu    sync.RWMutex
+    games map[string]registeredGame
+}
+
+func NewGameRegistry(newGame func() Game) *GameRegistry {
+    return &GameRegistry{
+        newGame: newGame,
+        now:     time.Now,
+        games:   map[string]registeredGame{},
+    }
+}
+
+// Start starts a new game and registers it under its ID until Remove is
+// called. A game that doesn't have an ID once started is given one.
+func (r *GameRegistry) Start(ctx context.Context, players []string, blinds BlindStructure, alertsDestination io.Writer) (string, Game) {
+    game := r.newGame()
+    game.Start(ctx, players, blinds, alertsDestination)
+
+    id := game.ID()
+    if id == "" {
+        id = newGameID()
+    }
+
+    info := ActiveGame{
+        ID:             id,
+        Players:        append([]string{}, players...),
+        BlindStructure: blinds.Name,
+        StartedAt:      r.now(),
+    }
+
+    r.mu.Lock()
+    r.games[id] = registeredGame{game, info}
+    r.mu.Unlock()
+
+    return id, game
+}
+
+func (r *GameRegistry) Get(id string) (Game, bool) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    registered, found := r.games[id]
+    return registered.game, found
+}
+
+func (r *GameRegistry) Remove(id string) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    delete(r.games, id)
+}
+
+// Describe says what the game with id is, and where its clock is at.
+func (r *GameRegistry) Describe(id string) (ActiveGame, bool) {
+    r.mu.RLock()
+    registered, found := r.games[id]
+    r.mu.RUnlock()
+
+    if !found {
+        return ActiveGame{}, false
+    }
+
+    return registered.describe(), true
+}
+
+// Active lists the registered games, oldest first, with where their clocks
+// are at.
+func (r *GameRegistry) Active() []ActiveGame {
+    r.mu.RLock()
+    registered := make([]registeredGame, 0, len(r.games))
+    for _, game := range r.games {
+        registered = append(registered, game)
+    }
+    r.mu.RUnlock()
+
+    active := make([]ActiveGame, 0, len(registered))
+
+    for _, game := range registered {
+        active = append(active, game.describe())
+    }
+
+    sort.Slice(active, func(i, j int) bool {
+        if !active[i].StartedAt.Equal(active[j].StartedAt) {
+            return active[i].StartedAt.Before(active[j].StartedAt)
+        }
+        return active[i].ID < active[j].ID
+    })
+
+    return active
+}
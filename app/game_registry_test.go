@@ -0,0 +1,81 @@
+package poker
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGameRegistry(t *testing.T) {
+    t.Run("starts a new game for each table", func(t *testing.T) {
+        var made []*GameSpy
+        registry := NewGameRegistry(func() Game {
+            game := &GameSpy{GameID: string(rune('a' + len(made)))}
+            made = append(made, game)
+            return game
+        })
+
+        firstID, first := registry.Start(context.Background(), []string{"Ruth", "Chris"}, TurboBlindStructure, io.Discard)
+        secondID, second := registry.Start(context.Background(), []string{"Cleo", "Tiest"}, DefaultBlindStructure, io.Discard)
+
+        if first == second || len(made) != 2 {
+            t.Fatal("expected each table to get its own game")
+        }
+
+        if firstID != "a" || secondID != "b" {
+            t.Errorf("got IDs %q and %q, want the games' own IDs a and b", firstID, secondID)
+        }
+
+        if got, _ := registry.Get(secondID); got != second {
+            t.Errorf("got %v for %s, want the second game", got, secondID)
+        }
+    })
+
+    t.Run("gives games without an ID one", func(t *testing.T) {
+        registry := NewGameRegistry(func() Game { return &GameSpy{} })
+
+        id, game := registry.Start(context.Background(), []string{"Ruth", "Chris"}, DefaultBlindStructure, io.Discard)
+
+        if id == "" {
+            t.Fatal("expected the game to be given an ID")
+        }
+
+        if got, found := registry.Get(id); !found || got != game {
+            t.Errorf("game not found under %s", id)
+        }
+    })
+
+    t.Run("lists the active games oldest first with their clocks", func(t *testing.T) {
+        clocks := []ClockState{{Level: 1}, {Level: 3}}
+        registry := NewGameRegistry(func() Game { return nil })
+
+        started := time.Date(2021, 2, 18, 20, 0, 0, 0, time.UTC)
+        for i, id := range []string{"b", "a"} {
+            game := &GameSpy{GameID: id, Clock: clocks[i]}
+            registry.newGame = SameGame(game)
+            registry.now = func() time.Time { return started.Add(time.Duration(i) * time.Minute) }
+            registry.Start(context.Background(), []string{"Ruth", "Chris"}, TurboBlindStructure, io.Discard)
+        }
+
+        want := []ActiveGame{
+            {ID: "b", Players: []string{"Ruth", "Chris"}, BlindStructure: "turbo", StartedAt: started, Clock: clocks[0]},
+            {ID: "a", Players: []string{"Ruth", "Chris"}, BlindStructure: "turbo", StartedAt: started.Add(time.Minute), Clock: clocks[1]},
+        }
+
+        if got := registry.Active(); !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+
+        registry.Remove("b")
+
+        if _, found := registry.Get("b"); found {
+            t.Error("expected the removed game to be gone")
+        }
+
+        if got := registry.Active(); len(got) != 1 || got[0].ID != "a" {
+            t.Errorf("got %+v, want only game a", got)
+        }
+    })
+}
@@ -0,0 +1,172 @@
+package poker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultResumeTimeout is how long a game waits for its dealer to reconnect
+// before it is abandoned.
+const DefaultResumeTimeout = time.Minute
+
+// DefaultGameExpiry is how long a game started without a dealer's connection
+// can run unfinished before it is abandoned.
+const DefaultGameExpiry = 12 * time.Hour
+
+var ErrUnknownSession = errors.New("no game to resume with that token")
+
+// gameSession is a game being dealt over /ws. It outlives the connection
+// that started it, so a dealer who reconnects with its token picks the game
+// back up. Blind alerts go to whichever connection is attached, if any.
+type gameSession struct {
+    id     string
+    token  string
+    game   Game
+    cancel context.CancelFunc
+
+    mu      sync.Mutex
+    ws      *playerServerWS
+    abandon *time.Timer
+    ended   bool
+}
+
+func newSessionToken() string {
+    token := make([]byte, 16)
+    rand.Read(token)
+    return hex.EncodeToString(token)
+}
+
+func (s *gameSession) Write(p []byte) (n int, err error) {
+    s.mu.Lock()
+    ws := s.ws
+    s.mu.Unlock()
+
+    if ws == nil {
+        return len(p), nil
+    }
+
+    return ws.Write(p)
+}
+
+func (s *gameSession) WriteBlindAlert(alert BlindAlert) error {
+    s.mu.Lock()
+    ws := s.ws
+    s.mu.Unlock()
+
+    if ws == nil {
+        return nil
+    }
+
+    return ws.WriteBlindAlert(alert)
+}
+
+// attach makes ws the session's connection, closing any connection it had
+// so that only one dealer controls the game.
+func (s *gameSession) attach(ws *playerServerWS) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if s.abandon != nil {
+        s.abandon.Stop()
+        s.abandon = nil
+    }
+
+    if s.ws != nil && s.ws != ws {
+        s.ws.Close()
+    }
+
+    s.ws = ws
+}
+
+// detach lets go of ws if it is still the session's connection, calling
+// abandon unless another connection attaches within timeout.
+func (s *gameSession) detach(ws *playerServerWS, timeout time.Duration, abandon func()) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if s.ended || s.ws != ws {
+        return
+    }
+
+    s.ws = nil
+    s.abandon = time.AfterFunc(timeout, abandon)
+}
+
+// expire calls abandon unless the session has ended within timeout, for a
+// game that has no connection to be abandoned along with.
+func (s *gameSession) expire(timeout time.Duration, abandon func()) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if s.ended {
+        return
+    }
+
+    s.abandon = time.AfterFunc(timeout, abandon)
+}
+
+// end stops the session's game, reporting false if it had already ended.
+func (s *gameSession) end() bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if s.ended {
+        return false
+    }
+
+    s.ended = true
+    s.ws = nil
+    if s.abandon != nil {
+        s.abandon.Stop()
+    }
+    s.cancel()
+
+    return true
+}
+
+// gameSessions are the running sessions, by the token they are resumed with
+// and by the ID of their game.
+type gameSessions struct {
+    mu       sync.Mutex
+    byToken  map[string]*gameSession
+    byGameID map[string]*gameSession
+}
+
+func newGameSessions() *gameSessions {
+    return &gameSessions{
+        byToken:  map[string]*gameSession{},
+        byGameID: map[string]*gameSession{},
+    }
+}
+
+func (g *gameSessions) add(session *gameSession) {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    g.byToken[session.token] = session
+    g.byGameID[session.id] = session
+}
+
+func (g *gameSessions) get(token string) (*gameSession, bool) {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    session, found := g.byToken[token]
+    return session, found
+}
+
+func (g *gameSessions) forGame(id string) (*gameSession, bool) {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    session, found := g.byGameID[id]
+    return session, found
+}
+
+func (g *gameSessions) remove(session *gameSession) {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    delete(g.byToken, session.token)
+    delete(g.byGameID, session.id)
+}
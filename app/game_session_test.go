@@ -0,0 +1,59 @@
+package poker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGameSession(t *testing.T) {
+    newSession := func() (*gameSession, *playerServerWS) {
+        ws := &playerServerWS{}
+        _, cancel := context.WithCancel(context.Background())
+        return &gameSession{token: newSessionToken(), cancel: cancel, ws: ws}, ws
+    }
+
+    t.Run("is abandoned when nobody reattaches in time", func(t *testing.T) {
+        session, ws := newSession()
+        abandoned := make(chan struct{})
+
+        session.detach(ws, time.Millisecond, func() { close(abandoned) })
+
+        select {
+        case <-abandoned:
+        case <-time.After(time.Second):
+            t.Error("expected the session to be abandoned")
+        }
+    })
+
+    t.Run("is kept when a connection reattaches in time", func(t *testing.T) {
+        session, ws := newSession()
+        abandoned := make(chan struct{})
+
+        session.detach(ws, 20*time.Millisecond, func() { close(abandoned) })
+        session.attach(ws)
+
+        select {
+        case <-abandoned:
+            t.Error("didn't expect the session to be abandoned")
+        case <-time.After(50 * time.Millisecond):
+        }
+    })
+
+    t.Run("ignores connections it has moved on from or after it has ended", func(t *testing.T) {
+        session, ws := newSession()
+        abandon := func() { t.Error("didn't expect the session to be abandoned") }
+
+        session.detach(&playerServerWS{}, time.Millisecond, abandon)
+
+        if !session.end() {
+            t.Error("expected the session to end")
+        }
+        if session.end() {
+            t.Error("expected ending twice to report the session had already ended")
+        }
+
+        session.detach(ws, time.Millisecond, abandon)
+        time.Sleep(10 * time.Millisecond)
+    })
+}
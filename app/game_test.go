@@ -1,11 +1,16 @@
 package poker
 
 import (
+	"context"
+	"errors"
 	"io"
+	"reflect"
 	"testing"
 	"time"
 )
 
+var fivePlayers = []string{"Ruth", "Chris", "Cleo", "Tiest", "Apollo"}
+
 func TestGame_Start(t *testing.T) {
     var dummyPlayerStore = &StubPlayerStore{}
 
@@ -13,7 +18,7 @@ func TestGame_Start(t *testing.T) {
 		blindAlerter := &SpyBlindAlerter{}
 		game := NewTexasHoldem(blindAlerter, dummyPlayerStore)
 
-		game.Start(5, io.Discard)
+		game.Start(context.Background(), fivePlayers, DefaultBlindStructure, io.Discard)
 
 		cases := []ScheduledAlert{
 			{At: 0 * time.Second, Amount: 100},
@@ -36,7 +41,7 @@ func TestGame_Start(t *testing.T) {
 		blindAlerter := &SpyBlindAlerter{}
 		game := NewTexasHoldem(blindAlerter, dummyPlayerStore)
 
-		game.Start(7, io.Discard)
+		game.Start(context.Background(), append(fivePlayers, "Alice", "Bob"), DefaultBlindStructure, io.Discard)
 
 		cases := []ScheduledAlert{
 			{At: 0 * time.Second, Amount: 100},
@@ -48,15 +53,250 @@ func TestGame_Start(t *testing.T) {
 		CheckSchedulingCases(t, cases, blindAlerter)
 	})
 
+	t.Run("schedules alerts from the blind structure's levels", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, dummyPlayerStore)
+
+		blinds := BlindStructure{
+			Name: "deep",
+			Levels: []BlindLevel{
+				{SmallBlind: 25, BigBlind: 50, Duration: 20 * time.Minute},
+				{SmallBlind: 50, BigBlind: 100, Ante: 10, Duration: 20 * time.Minute},
+				{SmallBlind: 75, BigBlind: 150, Ante: 15},
+			},
+		}
+
+		game.Start(context.Background(), fivePlayers, blinds, io.Discard)
+
+		cases := []ScheduledAlert{
+			{At: 0 * time.Second, Amount: 25},
+			{At: 20 * time.Minute, Amount: 50},
+			{At: 40 * time.Minute, Amount: 75},
+		}
+
+		CheckSchedulingCases(t, cases, blindAlerter)
+	})
+
+	t.Run("uses the default blind structure when none is given", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, dummyPlayerStore)
+
+		game.Start(context.Background(), fivePlayers, BlindStructure{}, io.Discard)
+
+		if len(blindAlerter.alerts) != len(DefaultBlindStructure.Levels) {
+			t.Errorf("got %d alerts, want %d", len(blindAlerter.alerts), len(DefaultBlindStructure.Levels))
+		}
+	})
 }
 
 func TestGame_Finish(t *testing.T) {
-    var dummyBlindAlerter = &SpyBlindAlerter{}
+	t.Run("records the winner", func(t *testing.T) {
+		var dummyBlindAlerter = &SpyBlindAlerter{}
+
+		store := &StubPlayerStore{}
+		game := NewTexasHoldem(dummyBlindAlerter, store)
+		winner := "Ruth"
+
+		game.Start(context.Background(), fivePlayers, DefaultBlindStructure, io.Discard)
+		game.Finish(winner)
+		AssertPlayerWin(t, store, winner)
+	})
+
+	t.Run("records the result of the game", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		game := NewTexasHoldem(&SpyBlindAlerter{}, store)
+
+		startedAt := time.Date(2021, 2, 18, 20, 0, 0, 0, time.UTC)
+		finishedAt := startedAt.Add(2 * time.Hour)
+
+		game.now = func() time.Time { return startedAt }
+		game.Start(context.Background(), fivePlayers, TurboBlindStructure, io.Discard)
+
+		game.now = func() time.Time { return finishedAt }
+		game.Finish("Ruth", "Chris", "Cleo")
+
+		if len(store.games) != 1 {
+			t.Fatalf("got %d games recorded, want 1", len(store.games))
+		}
+
+		got := store.games[0]
+
+		if got.ID == "" {
+			t.Error("expected the game to have an ID")
+		}
+
+		want := GameRecord{
+			ID:              got.ID,
+			StartedAt:       startedAt,
+			FinishedAt:      finishedAt,
+			NumberOfPlayers: 5,
+			Participants:    fivePlayers,
+			FinishingOrder:  []string{"Ruth", "Chris", "Cleo"},
+			BlindStructure:  TurboBlindStructure.Name,
+		}
+
+		assertGameRecord(t, got, want)
+		AssertPlayerWin(t, store, "Ruth")
+	})
+
+	t.Run("returns the error when the win can't be recorded", func(t *testing.T) {
+		store := &StubPlayerStore{winErr: errors.New("disk full")}
+		game := NewTexasHoldem(&SpyBlindAlerter{}, store)
+		game.Start(context.Background(), fivePlayers, DefaultBlindStructure, io.Discard)
+
+		err := game.Finish("Ruth")
+
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+
+		if len(store.games) != 0 {
+			t.Errorf("got %d games recorded, want none", len(store.games))
+		}
+	})
+
+	t.Run("keeps the game running when the result can't be recorded", func(t *testing.T) {
+		store := &StubPlayerStore{winErr: errors.New("disk full")}
+		game := NewTexasHoldem(&SpyBlindAlerter{}, store)
+		game.Start(context.Background(), fivePlayers, DefaultBlindStructure, io.Discard)
+
+		game.Finish("Ruth")
+		store.winErr = nil
+
+		assertNoError(t, game.Finish("Ruth"))
+		AssertPlayerWin(t, store, "Ruth")
+
+		if len(store.games) != 1 {
+			t.Errorf("got %d games recorded, want 1", len(store.games))
+		}
+	})
 
-	store := &StubPlayerStore{}
-	game := NewTexasHoldem(dummyBlindAlerter, store)
-	winner := "Ruth"
+	t.Run("fails when no game is running", func(t *testing.T) {
+		store := &StubPlayerStore{}
+		game := NewTexasHoldem(&SpyBlindAlerter{}, store)
 
-	game.Finish(winner)
-	AssertPlayerWin(t, store, winner)
-}
\ No newline at end of file
+		err := game.Finish("Ruth")
+
+		if err != ErrNoGameRunning {
+			t.Errorf("got error %v, want %v", err, ErrNoGameRunning)
+		}
+
+		if len(store.winCalls) != 0 {
+			t.Errorf("got %d wins recorded, want none", len(store.winCalls))
+		}
+	})
+
+	t.Run("rejects players who aren't in the game and keeps it running", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			winner   string
+			placings []string
+			want     error
+		}{
+			{"unknown winner", "Pies", nil, ErrUnknownPlayer},
+			{"unknown placing", "Ruth", []string{"Chris", "Pies"}, ErrUnknownPlayer},
+			{"placed twice", "Ruth", []string{"Chris", "Ruth"}, ErrDuplicatePlayer},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				store := &StubPlayerStore{}
+				game := NewTexasHoldem(&SpyBlindAlerter{}, store)
+				game.Start(context.Background(), fivePlayers, DefaultBlindStructure, io.Discard)
+
+				err := game.Finish(c.winner, c.placings...)
+
+				if !errors.Is(err, c.want) {
+					t.Errorf("got error %v, want %v", err, c.want)
+				}
+
+				if len(store.winCalls) != 0 {
+					t.Errorf("got %d wins recorded, want none", len(store.winCalls))
+				}
+
+				if _, err := game.ClockState(); err != nil {
+					t.Errorf("expected the game to still be running, %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("cancels the scheduled alerts", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, &StubPlayerStore{})
+
+		game.Start(context.Background(), fivePlayers, DefaultBlindStructure, io.Discard)
+		assertNotCancelled(t, blindAlerter.ctx)
+
+		game.Finish("Ruth")
+		assertCancelled(t, blindAlerter.ctx)
+	})
+
+	t.Run("cancels the scheduled alerts when the start context is done", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, &StubPlayerStore{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		game.Start(ctx, fivePlayers, DefaultBlindStructure, io.Discard)
+
+		cancel()
+		assertCancelled(t, blindAlerter.ctx)
+	})
+}
+
+func assertGameRecord(t testing.TB, got, want GameRecord) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got game %+v want %+v", got, want)
+	}
+}
+
+func assertCancelled(t testing.TB, ctx context.Context) {
+	t.Helper()
+	if ctx.Err() == nil {
+		t.Error("expected alerts to be cancelled but they were not")
+	}
+}
+
+func assertNotCancelled(t testing.TB, ctx context.Context) {
+	t.Helper()
+	if ctx.Err() != nil {
+		t.Errorf("expected alerts to still be scheduled but they were cancelled, %v", ctx.Err())
+	}
+}
+
+func TestGame_ControlClock(t *testing.T) {
+	t.Run("fails when no game is running", func(t *testing.T) {
+		game := NewTexasHoldem(&SpyBlindAlerter{}, &StubPlayerStore{})
+
+		_, err := game.ControlClock(PauseClock)
+
+		if err != ErrNoGameRunning {
+			t.Errorf("got error %v, want %v", err, ErrNoGameRunning)
+		}
+	})
+
+	t.Run("controls the running game's clock", func(t *testing.T) {
+		game := NewTexasHoldem(&SpyBlindAlerter{}, &StubPlayerStore{})
+		game.Start(context.Background(), fivePlayers, DefaultBlindStructure, io.Discard)
+
+		state, err := game.ControlClock(AdvanceClock)
+
+		assertNoError(t, err)
+		if state.Level != 2 || state.SmallBlind != 200 {
+			t.Errorf("got %v, want the second level", state)
+		}
+	})
+
+	t.Run("fails once the game has finished", func(t *testing.T) {
+		game := NewTexasHoldem(&SpyBlindAlerter{}, &StubPlayerStore{})
+		game.Start(context.Background(), fivePlayers, DefaultBlindStructure, io.Discard)
+		game.Finish("Ruth")
+
+		_, err := game.ClockState()
+
+		if err != ErrNoGameRunning {
+			t.Errorf("got error %v, want %v", err, ErrNoGameRunning)
+		}
+	})
+}
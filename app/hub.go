@@ -0,0 +1,131 @@
+package poker
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+var ErrSpectator = errors.New("spectators can't control the game")
+
+// spectatorBuffer is how many messages a spectator can fall behind by before
+// it starts missing them.
+const spectatorBuffer = 16
+
+// Hub broadcasts the messages of running games to any number of spectators
+// watching them. A spectator that falls behind misses messages rather than
+// holding up the game.
+type Hub struct {
+    mu       sync.Mutex
+    watchers map[string]map[chan Message]bool
+}
+
+func NewHub() *Hub {
+    return &Hub{watchers: map[string]map[chan Message]bool{}}
+}
+
+// Watch returns the messages broadcast for the game with id. The channel is
+// closed when the game is closed or stop is called.
+func (h *Hub) Watch(id string) (messages <-chan Message, stop func()) {
+    ch := make(chan Message, spectatorBuffer)
+
+    h.mu.Lock()
+    if h.watchers[id] == nil {
+        h.watchers[id] = map[chan Message]bool{}
+    }
+    h.watchers[id][ch] = true
+    h.mu.Unlock()
+
+    stop = func() {
+        h.mu.Lock()
+        defer h.mu.Unlock()
+
+        if h.watchers[id][ch] {
+            delete(h.watchers[id], ch)
+            close(ch)
+        }
+
+        if len(h.watchers[id]) == 0 {
+            delete(h.watchers, id)
+        }
+    }
+
+    return ch, stop
+}
+
+// Broadcast sends a message to everyone watching the game with id.
+func (h *Hub) Broadcast(id string, msgType MessageType, payload interface{}) error {
+    msg, err := NewMessage(msgType, payload)
+    if err != nil {
+        return err
+    }
+
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    for ch := range h.watchers[id] {
+        select {
+        case ch <- msg:
+        default:
+        }
+    }
+
+    return nil
+}
+
+// Close stops everyone watching the game with id.
+func (h *Hub) Close(id string) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    for ch := range h.watchers[id] {
+        close(ch)
+    }
+    delete(h.watchers, id)
+}
+
+// gameFeed is the BlindAlertWriter that broadcasts a game's blind alerts to
+// its spectators. The game's clock starts before its ID is known, so the
+// last alert written before then is held back and broadcast once it is.
+type gameFeed struct {
+    hub *Hub
+
+    mu      sync.Mutex
+    id      string
+    pending *BlindPayload
+}
+
+func (f *gameFeed) setID(id string) {
+    f.mu.Lock()
+    f.id = id
+    pending := f.pending
+    f.pending = nil
+    f.mu.Unlock()
+
+    if pending != nil {
+        f.hub.Broadcast(id, BlindMessage, *pending)
+    }
+}
+
+func (f *gameFeed) Write(p []byte) (n int, err error) {
+    f.broadcast(BlindPayload{Message: strings.TrimSpace(string(p))})
+    return len(p), nil
+}
+
+func (f *gameFeed) WriteBlindAlert(alert BlindAlert) error {
+    f.broadcast(newBlindPayload(alert))
+    return nil
+}
+
+func (f *gameFeed) broadcast(payload BlindPayload) {
+    f.mu.Lock()
+    id := f.id
+    if id == "" {
+        f.pending = &payload
+    }
+    f.mu.Unlock()
+
+    if id != "" {
+        f.hub.Broadcast(id, BlindMessage, payload)
+    }
+}
@@ -0,0 +1,126 @@
+package poker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHub(t *testing.T) {
+    t.Run("broadcasts to everyone watching the game", func(t *testing.T) {
+        hub := NewHub()
+
+        first, stopFirst := hub.Watch("abc")
+        defer stopFirst()
+        second, stopSecond := hub.Watch("abc")
+        defer stopSecond()
+        other, stopOther := hub.Watch("xyz")
+        defer stopOther()
+
+        assertNoError(t, hub.Broadcast("abc", BlindMessage, BlindPayload{Message: "Blind is now 200"}))
+
+        assertHubSent(t, first, BlindMessage)
+        assertHubSent(t, second, BlindMessage)
+
+        select {
+        case msg := <-other:
+            t.Errorf("got %v for another game", msg)
+        default:
+        }
+    })
+
+    t.Run("closing a game stops its spectators", func(t *testing.T) {
+        hub := NewHub()
+
+        messages, stop := hub.Watch("abc")
+        hub.Close("abc")
+
+        if _, ok := <-messages; ok {
+            t.Error("expected the spectator's messages to be closed")
+        }
+
+        // stopping after the game has closed is fine
+        stop()
+    })
+
+    t.Run("spectators who fall behind miss messages rather than hold up the game", func(t *testing.T) {
+        hub := NewHub()
+
+        messages, stop := hub.Watch("abc")
+        defer stop()
+
+        for i := 0; i < spectatorBuffer*2; i++ {
+            hub.Broadcast("abc", StateMessage, StatePayload{Level: i})
+        }
+
+        if len(messages) != spectatorBuffer {
+            t.Errorf("got %d messages waiting, want %d", len(messages), spectatorBuffer)
+        }
+    })
+
+    t.Run("a game's feed broadcasts its blind alerts with the level's blinds", func(t *testing.T) {
+        hub := NewHub()
+
+        messages, stop := hub.Watch("abc")
+        defer stop()
+
+        feed := &gameFeed{hub: hub}
+        feed.setID("abc")
+        feed.WriteBlindAlert(BlindAlert{3, BlindLevel{SmallBlind: 300, BigBlind: 600, Ante: 50, Duration: 15 * time.Minute}})
+
+        var got BlindPayload
+        assertNoError(t, (<-messages).DecodePayload(&got))
+
+        want := BlindPayload{
+            Message:         "Level 3, blinds 300/600 ante 50",
+            Level:           3,
+            SmallBlind:      300,
+            BigBlind:        600,
+            Ante:            50,
+            DurationSeconds: 900,
+        }
+
+        if got != want {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+
+    t.Run("a game's feed broadcasts the opening level once it knows the game's ID", func(t *testing.T) {
+        hub := NewHub()
+
+        messages, stop := hub.Watch("abc")
+        defer stop()
+
+        feed := &gameFeed{hub: hub}
+        feed.WriteBlindAlert(BlindAlert{1, BlindLevel{SmallBlind: 100, BigBlind: 200}})
+
+        if len(messages) != 0 {
+            t.Fatalf("got %d messages before the feed knew its game, want none", len(messages))
+        }
+
+        feed.setID("abc")
+
+        if len(messages) != 1 {
+            t.Fatalf("got %d messages once the feed knew its game, want 1", len(messages))
+        }
+
+        var got BlindPayload
+        assertNoError(t, (<-messages).DecodePayload(&got))
+
+        if got.Level != 1 {
+            t.Errorf("got level %d, want the opening level", got.Level)
+        }
+    })
+}
+
+func assertHubSent(t testing.TB, messages <-chan Message, want MessageType) {
+    t.Helper()
+
+    select {
+    case msg := <-messages:
+        if msg.Type != want {
+            t.Errorf("got a %s message, want %s", msg.Type, want)
+        }
+    default:
+        t.Errorf("got no message, want %s", want)
+    }
+}
@@ -0,0 +1,247 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// InMemoryPlayerStore keeps the league and game results in memory. It can
+// snapshot them as JSON in the format NewLeague reads, and be restored from
+// such a snapshot. It is safe to use from multiple goroutines.
+type InMemoryPlayerStore struct {
+    mu     sync.RWMutex
+    league League
+    games   []GameRecord
+    wins    []Win
+    ratings Ratings
+    now     func() time.Time
+}
+
+// NewInMemoryPlayerStore creates a store whose league starts with players.
+func NewInMemoryPlayerStore(players ...Player) *InMemoryPlayerStore {
+    return &InMemoryPlayerStore{league: append(League{}, players...), now: time.Now}
+}
+
+// NewInMemoryPlayerStoreFromSnapshot restores a store from a snapshot, or from
+// any league NewLeague can read.
+func NewInMemoryPlayerStoreFromSnapshot(rdr io.Reader) (*InMemoryPlayerStore, error) {
+    db, err := newDatabase(rdr)
+
+    if err != nil {
+        return nil, fmt.Errorf("problem restoring player store, %v", err)
+    }
+
+    return &InMemoryPlayerStore{league: db.League, games: db.Games, wins: db.Wins, ratings: db.Ratings, now: time.Now}, nil
+}
+
+// InMemoryPlayerStoreFromFile restores a store from the snapshot at path. If
+// there's no file at path the store starts empty. Closing saves a snapshot
+// back to path.
+func InMemoryPlayerStoreFromFile(path string) (*InMemoryPlayerStore, func(), error) {
+    store := NewInMemoryPlayerStore()
+
+    file, err := os.Open(path)
+
+    switch {
+    case os.IsNotExist(err):
+    case err != nil:
+        return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
+    default:
+        store, err = NewInMemoryPlayerStoreFromSnapshot(file)
+        file.Close()
+
+        if err != nil {
+            return nil, nil, fmt.Errorf("problem loading player store from file %s, %v", path, err)
+        }
+    }
+
+    closeFunc := func() {
+        if err := store.SaveSnapshot(path); err != nil {
+            log.Printf("problem saving player store, %v\n", err)
+        }
+    }
+
+    return store, closeFunc, nil
+}
+
+// Snapshot writes the league, games and ratings as JSON.
+func (i *InMemoryPlayerStore) Snapshot(w io.Writer) error {
+    i.mu.RLock()
+    defer i.mu.RUnlock()
+
+    return json.NewEncoder(w).Encode(database{i.league, i.games, i.wins, i.ratings})
+}
+
+// SaveSnapshot replaces the file at path with a snapshot of the store. The
+// file is left as it was if the snapshot can't be written.
+func (i *InMemoryPlayerStore) SaveSnapshot(path string) error {
+    if err := i.Snapshot(newTape(path)); err != nil {
+        return fmt.Errorf("problem saving snapshot to %s, %v", path, err)
+    }
+    return nil
+}
+
+func (i *InMemoryPlayerStore) GetPlayer(name string) (Player, bool) {
+    i.mu.RLock()
+    defer i.mu.RUnlock()
+
+    if player := i.league.Find(name); player != nil {
+        return *player, true
+    }
+    return Player{}, false
+}
+
+func (i *InMemoryPlayerStore) GetPlayerScore(name string) int {
+    i.mu.RLock()
+    defer i.mu.RUnlock()
+
+    if player := i.league.Find(name); player != nil {
+        return player.Wins
+    }
+    return 0
+}
+
+func (i *InMemoryPlayerStore) RecordWin(name string) error {
+    i.mu.Lock()
+    defer i.mu.Unlock()
+
+    i.league = i.league.withWin(name)
+    i.wins = append(i.wins, Win{name, i.now()})
+    return nil
+}
+
+// GetLeague returns a copy of the part of the league asked for, ranked.
+func (i *InMemoryPlayerStore) GetLeague(query LeagueQuery) Standings {
+    i.mu.RLock()
+    defer i.mu.RUnlock()
+
+    league := i.league
+    if !query.Window.IsAllTime() {
+        league = league.in(query.Window, i.wins, i.games)
+    }
+    return league.query(query, i.wins, i.games)
+}
+
+// GetLeagueIn returns the league counting only the wins and games in window,
+// ranked by wins.
+func (i *InMemoryPlayerStore) GetLeagueIn(window Window) League {
+    return i.GetLeague(LeagueQuery{Window: window}).Players()
+}
+
+// RecordGame stores the result of a finished game, giving it an ID if it
+// doesn't have one, and adds a game played for everyone who played in it.
+func (i *InMemoryPlayerStore) RecordGame(game GameRecord) (GameRecord, error) {
+    return i.recordGame(game, false)
+}
+
+// RecordResult records a finished game as RecordGame does and a win for its
+// winner as RecordWin does, both at once.
+func (i *InMemoryPlayerStore) RecordResult(game GameRecord) (GameRecord, error) {
+    return i.recordGame(game, true)
+}
+
+func (i *InMemoryPlayerStore) recordGame(game GameRecord, withWin bool) (GameRecord, error) {
+    if game.ID == "" {
+        game.ID = newGameID()
+    }
+
+    i.mu.Lock()
+    defer i.mu.Unlock()
+
+    if winner := game.Winner(); withWin && winner != "" {
+        i.league = i.league.withWin(winner)
+        i.wins = append(i.wins, Win{winner, i.now()})
+    }
+
+    i.games = append(i.games, game)
+    i.league = i.league.withGamePlayed(gamePlayers(game))
+    i.ratings = i.ratings.with(game)
+    return game, nil
+}
+
+// GetRatings returns a copy of everyone's ratings.
+func (i *InMemoryPlayerStore) GetRatings() Ratings {
+    i.mu.RLock()
+    defer i.mu.RUnlock()
+
+    return i.ratings.copy()
+}
+
+// GetGames returns a copy of every game recorded, oldest first.
+func (i *InMemoryPlayerStore) GetGames() []GameRecord {
+    i.mu.RLock()
+    defer i.mu.RUnlock()
+
+    return append([]GameRecord{}, i.games...)
+}
+
+func (i *InMemoryPlayerStore) GetGame(id string) (GameRecord, bool) {
+    i.mu.RLock()
+    defer i.mu.RUnlock()
+
+    for _, game := range i.games {
+        if game.ID == id {
+            return game, true
+        }
+    }
+    return GameRecord{}, false
+}
+
+// RenamePlayer renames the player named from to, in the league and in the
+// games they played.
+func (i *InMemoryPlayerStore) RenamePlayer(from, to string) error {
+    i.mu.Lock()
+    defer i.mu.Unlock()
+
+    league, err := i.league.renamed(from, to)
+    if err != nil {
+        return err
+    }
+
+    i.league = league
+    i.games, _ = renamedInGames(i.games, from, to)
+    i.wins = renamedInWins(i.wins, from, to)
+    i.ratings = RateGames(i.games)
+    return nil
+}
+
+// MergePlayers adds the wins and games of the player named from to the
+// player named into, and removes from.
+func (i *InMemoryPlayerStore) MergePlayers(from, into string) error {
+    i.mu.Lock()
+    defer i.mu.Unlock()
+
+    games, shared := renamedInGames(i.games, from, into)
+
+    league, err := i.league.merged(from, into, shared)
+    if err != nil {
+        return err
+    }
+
+    i.league = league
+    i.games = games
+    i.wins = renamedInWins(i.wins, from, into)
+    i.ratings = RateGames(i.games)
+    return nil
+}
+
+// DeletePlayer removes the player from the league. The games they played are
+// kept as they were.
+func (i *InMemoryPlayerStore) DeletePlayer(name string) error {
+    i.mu.Lock()
+    defer i.mu.Unlock()
+
+    league, err := i.league.without(name)
+    if err != nil {
+        return err
+    }
+
+    i.league = league
+    i.wins = winsWithout(i.wins, name)
+    return nil
+}
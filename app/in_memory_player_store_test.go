@@ -0,0 +1,63 @@
+package poker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInMemoryPlayerStore(t *testing.T) {
+    t.Run("starts with the players it is given", func(t *testing.T) {
+        store := NewInMemoryPlayerStore(Player{"Cleo", 10, 0}, Player{"Chris", 33, 0})
+
+        assertLeague(t, store.GetLeague(WholeLeague).Players(), []Player{{"Chris", 33, 0}, {"Cleo", 10, 0}})
+    })
+
+    t.Run("restores from a league NewLeague reads", func(t *testing.T) {
+        store, err := NewInMemoryPlayerStoreFromSnapshot(strings.NewReader(`[
+            {"Name": "Cleo", "Wins": 10},
+            {"Name": "Chris", "Wins": 33}]`))
+
+        assertNoError(t, err)
+        assertLeague(t, store.GetLeague(WholeLeague).Players(), []Player{{"Chris", 33, 0}, {"Cleo", 10, 0}})
+    })
+
+    t.Run("snapshots in a format NewLeague reads", func(t *testing.T) {
+        store := NewInMemoryPlayerStore(Player{"Cleo", 10, 0})
+        snapshot := &strings.Builder{}
+
+        assertNoError(t, store.Snapshot(snapshot))
+
+        league, err := NewLeague(strings.NewReader(snapshot.String()))
+
+        assertNoError(t, err)
+        assertLeague(t, league, []Player{{"Cleo", 10, 0}})
+    })
+
+    t.Run("saves a snapshot to file on close and restores it", func(t *testing.T) {
+        path := filepath.Join(t.TempDir(), "tournament.json")
+
+        store, closeStore, err := InMemoryPlayerStoreFromFile(path)
+        assertNoError(t, err)
+
+        store.RecordWin("Pepper")
+        closeStore()
+
+        restored, _, err := InMemoryPlayerStoreFromFile(path)
+        assertNoError(t, err)
+
+        assertScoreEquals(t, restored.GetPlayerScore("Pepper"), 1)
+    })
+
+    t.Run("reports a snapshot it can't read", func(t *testing.T) {
+        path := filepath.Join(t.TempDir(), "tournament.json")
+        os.WriteFile(path, []byte("not json"), 0666)
+
+        _, _, err := InMemoryPlayerStoreFromFile(path)
+
+        if err == nil {
+            t.Error("expected an error but didn't get one")
+        }
+    })
+}
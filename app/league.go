@@ -1,13 +1,231 @@
 package poker
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+    ErrPlayerNotFound  = errors.New("no player named")
+    ErrPlayerExists    = errors.New("there is already a player named")
+    ErrSamePlayer      = errors.New("can't merge a player into themselves")
+    ErrUnknownTieBreak = errors.New("unknown tie-break")
 )
 
 type League []Player
 
+// LeagueOrder is how a league is sorted.
+type LeagueOrder string
+
+const (
+    // ByWins sorts by wins, most first, which is the default.
+    ByWins LeagueOrder = "wins"
+    // ByName sorts by name, A to Z.
+    ByName LeagueOrder = "name"
+)
+
+// TieBreak is how players level on wins are ordered in the league.
+type TieBreak string
+
+const (
+    // TieBreakJoined keeps players level on wins in the order they joined the
+    // league, which is the default.
+    TieBreakJoined TieBreak = "joined"
+    // TieBreakName orders players level on wins by name, A to Z.
+    TieBreakName TieBreak = "name"
+    // TieBreakLastWin puts whoever won most recently first.
+    TieBreakLastWin TieBreak = "last-win"
+    // TieBreakHeadToHead puts whoever did best in the games they played
+    // against the others level with them first, see matchScore.
+    TieBreakHeadToHead TieBreak = "head-to-head"
+)
+
+// ParseTieBreak reads a tie-break from its name, the default if it's empty.
+func ParseTieBreak(name string) (TieBreak, error) {
+    switch tieBreak := TieBreak(name); tieBreak {
+    case "":
+        return TieBreakJoined, nil
+    case TieBreakJoined, TieBreakName, TieBreakLastWin, TieBreakHeadToHead:
+        return tieBreak, nil
+    default:
+        return "", fmt.Errorf("%w %q, want %s, %s, %s or %s", ErrUnknownTieBreak, name, TieBreakJoined, TieBreakName, TieBreakLastWin, TieBreakHeadToHead)
+    }
+}
+
+// LeagueQuery asks for part of the league: the players whose names start
+// with NamePrefix, counting only wins and games in Window, sorted by Order
+// with ties on wins broken by TieBreak, and Limit of them after skipping the
+// first Offset. Prefixes are matched case sensitively and a Limit of 0 is no
+// limit.
+type LeagueQuery struct {
+    Window     Window
+    NamePrefix string
+    Order      LeagueOrder
+    TieBreak   TieBreak
+    Offset     int
+    Limit      int
+}
+
+// WholeLeague asks for the whole all-time league, sorted by wins.
+var WholeLeague = LeagueQuery{}
+
+// Standing is a player's place in the league. Players the tie-break can't
+// tell apart share a Rank, and the players after them skip the places they
+// share, so two players level in second are followed by the fourth. Breaking
+// ties by joining or by name only orders players, it never tells them apart.
+type Standing struct {
+    Player
+    Rank int
+}
+
+// Standings is the league with everyone's place in it.
+type Standings []Standing
+
+// Players returns the players in s, in order.
+func (s Standings) Players() League {
+    league := make(League, 0, len(s))
+
+    for _, standing := range s {
+        league = append(league, standing.Player)
+    }
+
+    return league
+}
+
+// page returns limit standings after skipping offset, or all of the rest if
+// limit is 0.
+func (s Standings) page(offset, limit int) Standings {
+    if offset >= len(s) {
+        return Standings{}
+    }
+
+    s = s[offset:]
+
+    if limit > 0 && limit < len(s) {
+        s = s[:limit]
+    }
+
+    return s
+}
+
+// query returns the part of l asked for by q, whose window l is already
+// counted for. Players keep their place in the whole league when it is
+// filtered, sorted by name or paged. wins and games are all those recorded,
+// for breaking ties.
+func (l League) query(q LeagueQuery, wins []Win, games []GameRecord) Standings {
+    standings := Standings{}
+
+    for _, standing := range l.ranked(q.TieBreak, q.Window, wins, games) {
+        if strings.HasPrefix(standing.Name, q.NamePrefix) {
+            standings = append(standings, standing)
+        }
+    }
+
+    if q.Order == ByName {
+        sort.SliceStable(standings, func(i, j int) bool {
+            return standings[i].Name < standings[j].Name
+        })
+    }
+
+    return standings.page(q.Offset, q.Limit)
+}
+
+// ranked sorts l by wins, most first, breaking ties by tieBreak with the wins
+// and games in window, and ranks it.
+func (l League) ranked(tieBreak TieBreak, window Window, wins []Win, games []GameRecord) Standings {
+    level := func(a, b Player) int { return 0 }
+
+    switch tieBreak {
+    case TieBreakLastWin:
+        lastWins := lastWinsIn(wins, window)
+        level = func(a, b Player) int {
+            return lastWins[a.Name].Compare(lastWins[b.Name])
+        }
+    case TieBreakHeadToHead:
+        points := l.headToHeadPoints(gamesIn(games, window))
+        level = func(a, b Player) int {
+            return cmp.Compare(points[a.Name], points[b.Name])
+        }
+    }
+
+    league := append(League{}, l...)
+
+    sort.SliceStable(league, func(i, j int) bool {
+        a, b := league[i], league[j]
+
+        if a.Wins != b.Wins {
+            return a.Wins > b.Wins
+        }
+        if broken := level(a, b); broken != 0 {
+            return broken > 0
+        }
+        return tieBreak == TieBreakName && a.Name < b.Name
+    })
+
+    standings := make(Standings, 0, len(league))
+
+    for i, player := range league {
+        rank := i + 1
+
+        if i > 0 && player.Wins == league[i-1].Wins && level(player, league[i-1]) == 0 {
+            rank = standings[i-1].Rank
+        }
+
+        standings = append(standings, Standing{player, rank})
+    }
+
+    return standings
+}
+
+// headToHeadPoints is how many matches, see matchScore, each player in l won
+// in games against the players level with them on wins, a tie being worth
+// half a match.
+func (l League) headToHeadPoints(games []GameRecord) map[string]float64 {
+    wins := map[string]int{}
+    for _, player := range l {
+        wins[player.Name] = player.Wins
+    }
+
+    points := map[string]float64{}
+
+    for _, game := range games {
+        players := gamePlayers(game)
+
+        for _, player := range players {
+            for _, opponent := range players {
+                mine, playerFound := wins[player]
+                theirs, opponentFound := wins[opponent]
+
+                if player != opponent && playerFound && opponentFound && mine == theirs {
+                    points[player] += matchScore(game, player, opponent)
+                }
+            }
+        }
+    }
+
+    return points
+}
+
+// lastWinsIn is when each player last won in window.
+func lastWinsIn(wins []Win, window Window) map[string]time.Time {
+    last := map[string]time.Time{}
+
+    for _, win := range wins {
+        if window.Contains(win.At) && win.At.After(last[win.Name]) {
+            last[win.Name] = win.At
+        }
+    }
+
+    return last
+}
+
 func (l League) Find(name string) *Player {
     for i, p := range l {
         if p.Name==name {
@@ -17,14 +235,228 @@ func (l League) Find(name string) *Player {
     return nil
 }
 
+// FindNormalized finds the first player whose name is the same as name once
+// both are normalized, so "chris" and " Chris " both find "Chris". See
+// NormalizeName.
+func (l League) FindNormalized(name string) *Player {
+    normalized := NormalizeName(name)
+
+    for i, p := range l {
+        if NormalizeName(p.Name) == normalized {
+            return &l[i]
+        }
+    }
+    return nil
+}
+
+// NormalizeName lower cases name and collapses its runs of spaces, for
+// telling apart players who are really the same person.
+func NormalizeName(name string) string {
+    return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// withWin returns a copy of the league with a win added for name.
+func (l League) withWin(name string) League {
+    league := append(League{}, l...)
+
+    if player := league.Find(name); player != nil {
+        player.Wins++
+    } else {
+        league = append(league, Player{name, 1, 0})
+    }
+
+    return league
+}
+
+// withGamePlayed returns a copy of the league with a game played added for
+// each of names.
+func (l League) withGamePlayed(names []string) League {
+    league := append(League{}, l...)
+
+    for _, name := range names {
+        if player := league.Find(name); player != nil {
+            player.Played++
+        } else {
+            league = append(league, Player{name, 0, 1})
+        }
+    }
+
+    return league
+}
+
+// renamed returns a copy of the league with the player named from renamed to,
+// keeping their place in it.
+func (l League) renamed(from, to string) (League, error) {
+    if strings.TrimSpace(to) == "" {
+        return nil, ErrNoPlayerName
+    }
+
+    league := append(League{}, l...)
+
+    player := league.Find(from)
+    if player == nil {
+        return nil, fmt.Errorf("%w %q", ErrPlayerNotFound, from)
+    }
+
+    if from != to && league.Find(to) != nil {
+        return nil, fmt.Errorf("%w %q", ErrPlayerExists, to)
+    }
+
+    player.Name = to
+    return league, nil
+}
+
+// merged returns a copy of the league with the wins and games of the player
+// named from added to the player named into, and from taken out. shared is
+// how many games they played in together, which into has only played once.
+// Merging into a player who isn't in the league renames from.
+func (l League) merged(from, into string, shared int) (League, error) {
+    if from == into {
+        return nil, fmt.Errorf("%w, %q", ErrSamePlayer, from)
+    }
+
+    if l.Find(into) == nil {
+        return l.renamed(from, into)
+    }
+
+    source := l.Find(from)
+    if source == nil {
+        return nil, fmt.Errorf("%w %q", ErrPlayerNotFound, from)
+    }
+
+    league, _ := l.without(from)
+
+    target := league.Find(into)
+    target.Wins += source.Wins
+    target.Played += source.Played - shared
+
+    return league, nil
+}
+
+// without returns a copy of the league with the player named name taken out.
+func (l League) without(name string) (League, error) {
+    if l.Find(name) == nil {
+        return nil, fmt.Errorf("%w %q", ErrPlayerNotFound, name)
+    }
+
+    league := League{}
+    for _, p := range l {
+        if p.Name != name {
+            league = append(league, p)
+        }
+    }
+
+    return league, nil
+}
+
+// Win is a win recorded for a player at a time, kept so that the league can
+// be worked out for a window of time as well as for all time.
+type Win struct {
+    Name string
+    At   time.Time
+}
+
+// renamedInWins returns a copy of wins with the wins of the player named from
+// given to the player named to.
+func renamedInWins(wins []Win, from, to string) []Win {
+    renamed := make([]Win, 0, len(wins))
+
+    for _, win := range wins {
+        if win.Name == from {
+            win.Name = to
+        }
+        renamed = append(renamed, win)
+    }
+
+    return renamed
+}
+
+// winsWithout returns a copy of wins without the wins of the player named
+// name.
+func winsWithout(wins []Win, name string) []Win {
+    kept := make([]Win, 0, len(wins))
+
+    for _, win := range wins {
+        if win.Name != name {
+            kept = append(kept, win)
+        }
+    }
+
+    return kept
+}
+
+// in returns the league counting only the wins and the games finished in
+// window. Only players in l who won or played in the window are in it.
+func (l League) in(window Window, wins []Win, games []GameRecord) League {
+    counted := League{}
+
+    for _, player := range l {
+        player.Wins, player.Played = 0, 0
+
+        for _, win := range wins {
+            if win.Name == player.Name && window.Contains(win.At) {
+                player.Wins++
+            }
+        }
+
+        for _, game := range games {
+            if window.Contains(game.FinishedAt) && containsName(gamePlayers(game), player.Name) {
+                player.Played++
+            }
+        }
+
+        if player.Wins > 0 || player.Played > 0 {
+            counted = append(counted, player)
+        }
+    }
+
+    return counted
+}
+
+// NewLeague reads a league written as a JSON array of players, or the league
+// out of a whole player database.
 func NewLeague(rdr io.Reader) ([]Player, error) {
-	var league []Player
+	db, err := newDatabase(rdr)
+	return db.League, err
+}
 
-	err := json.NewDecoder(rdr).Decode(&league)
+// database is everything a player store keeps: the league, the results of
+// the games played, when each win was recorded and everyone's ratings.
+// Databases written before wins were recorded have wins that are only in the
+// league, and those written before ratings were kept have them worked out
+// from their games when read.
+type database struct {
+	League  League
+	Games   []GameRecord
+	Wins    []Win
+	Ratings Ratings
+}
+
+// newDatabase reads a database written as JSON. A JSON array is read as a
+// league with no games, which is how databases used to be written.
+func newDatabase(rdr io.Reader) (database, error) {
+	var raw json.RawMessage
+
+	if err := json.NewDecoder(rdr).Decode(&raw); err != nil {
+		return database{}, fmt.Errorf("problem parsing league, %v", err)
+	}
+
+	var db database
+	var err error
+
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(raw, &db.League)
+	} else {
+		err = json.Unmarshal(raw, &db)
+	}
 
 	if err != nil {
-		err = fmt.Errorf("problem parsing league, %v", err)
+		return database{}, fmt.Errorf("problem parsing league, %v", err)
+	}
+
+	if db.Ratings == nil {
+		db.Ratings = RateGames(db.Games)
 	}
 
-	return league, err
+	return db, nil
 }
\ No newline at end of file
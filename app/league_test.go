@@ -0,0 +1,35 @@
+package poker
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTieBreak(t *testing.T) {
+    t.Run("reads tie-breaks by name, joining by default", func(t *testing.T) {
+        cases := map[string]TieBreak{
+            "":             TieBreakJoined,
+            "joined":       TieBreakJoined,
+            "name":         TieBreakName,
+            "last-win":     TieBreakLastWin,
+            "head-to-head": TieBreakHeadToHead,
+        }
+
+        for name, want := range cases {
+            got, err := ParseTieBreak(name)
+            assertNoError(t, err)
+
+            if got != want {
+                t.Errorf("got %q for %q, want %q", got, name, want)
+            }
+        }
+    })
+
+    t.Run("rejects unknown tie-breaks", func(t *testing.T) {
+        _, err := ParseTieBreak("coin-toss")
+
+        if !errors.Is(err, ErrUnknownTieBreak) {
+            t.Errorf("got error %v, want %v", err, ErrUnknownTieBreak)
+        }
+    })
+}
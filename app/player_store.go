@@ -0,0 +1,53 @@
+package poker
+
+import (
+	"strings"
+)
+
+// OpenPlayerStore opens the store described by spec, one of "json:path",
+// "sqlite:path", "memory" or "memory:path". A memory store with a path is
+// restored from and snapshotted to the file at path. A spec that doesn't
+// start with one of those kinds is the path of a JSON file, colons and all.
+func OpenPlayerStore(spec string) (PlayerStore, func(), error) {
+    kind, path := storeKind(spec)
+
+    switch kind {
+    case "sqlite":
+        store, closeFunc, err := SQLPlayerStoreFromFile(path)
+        if err != nil {
+            return nil, nil, err
+        }
+        return store, closeFunc, nil
+    case "memory":
+        if path == "" {
+            return NewInMemoryPlayerStore(), func() {}, nil
+        }
+        store, closeFunc, err := InMemoryPlayerStoreFromFile(path)
+        if err != nil {
+            return nil, nil, err
+        }
+        return store, closeFunc, nil
+    default:
+        store, closeFunc, err := FileSystemPlayerStoreFromFile(path)
+        if err != nil {
+            return nil, nil, err
+        }
+        return store, closeFunc, nil
+    }
+}
+
+// storeKind splits spec into the kind of store and its path, only on a kind
+// it knows so that paths like C:\db.json are left whole.
+func storeKind(spec string) (kind, path string) {
+    if spec == "memory" {
+        return spec, ""
+    }
+
+    for _, kind := range []string{"json", "sqlite", "memory"} {
+        if path, ok := strings.CutPrefix(spec, kind+":"); ok {
+            return kind, path
+        }
+    }
+
+    return "json", spec
+}
@@ -0,0 +1,616 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// PlayerStoreOpener opens a PlayerStore on some storage. Every call opens the
+// same storage, so a second call sees what was stored through the first as
+// if the program had been restarted.
+type PlayerStoreOpener func() PlayerStore
+
+// TestPlayerStoreContract checks the behaviour every PlayerStore must have.
+// newStorage is called by each check to create empty storage for it.
+func TestPlayerStoreContract(t *testing.T, newStorage func(t *testing.T) PlayerStoreOpener) {
+    t.Run("an empty store has an empty league", func(t *testing.T) {
+        store := newStorage(t)()
+
+        if league := store.GetLeague(WholeLeague); len(league) != 0 {
+            t.Errorf("got league %v, want it empty", league)
+        }
+    })
+
+    t.Run("unknown players have no wins", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractScore(t, store, "Apollo", 0)
+    })
+
+    t.Run("players are found once they have won or played", func(t *testing.T) {
+        store := newStorage(t)()
+
+        if _, found := store.GetPlayer("Pepper"); found {
+            t.Error("didn't expect to find a player who has never played")
+        }
+
+        assertContractNoError(t, store.RecordWin("Pepper"))
+        _, err := store.RecordGame(contractGame())
+        assertContractNoError(t, err)
+
+        if got, found := store.GetPlayer("Pepper"); !found || got != (Player{"Pepper", 1, 0}) {
+            t.Errorf("got %+v found %v, want Pepper with one win", got, found)
+        }
+
+        if got, found := store.GetPlayer("Ruth"); !found || got != (Player{"Ruth", 0, 1}) {
+            t.Errorf("got %+v found %v, want Ruth with no wins from one game", got, found)
+        }
+    })
+
+    t.Run("recording a win adds new players with one win", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("Pepper"))
+
+        assertContractScore(t, store, "Pepper", 1)
+    })
+
+    t.Run("a win recorded on its own counts no game played", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("Pepper"))
+
+        player, _ := store.GetPlayer("Pepper")
+
+        if player.Wins != 1 || player.Played != 0 {
+            t.Errorf("got %+v, want 1 win and no games played", player)
+        }
+    })
+
+    t.Run("recording a win increments existing players' wins", func(t *testing.T) {
+        store := newStorage(t)()
+
+        for i := 0; i < 3; i++ {
+            assertContractNoError(t, store.RecordWin("Pepper"))
+        }
+        assertContractNoError(t, store.RecordWin("Floyd"))
+
+        assertContractScore(t, store, "Pepper", 3)
+        assertContractScore(t, store, "Floyd", 1)
+    })
+
+    t.Run("the league is sorted by wins, most first", func(t *testing.T) {
+        store := newStorage(t)()
+
+        wins := map[string]int{"Cleo": 2, "Chris": 3, "Tiest": 1}
+        for _, name := range []string{"Cleo", "Chris", "Tiest"} {
+            for i := 0; i < wins[name]; i++ {
+                assertContractNoError(t, store.RecordWin(name))
+            }
+        }
+
+        assertContractLeague(t, store.GetLeague(WholeLeague), League{{"Chris", 3, 0}, {"Cleo", 2, 0}, {"Tiest", 1, 0}})
+    })
+
+    t.Run("changing the league returned doesn't change the store", func(t *testing.T) {
+        store := newStorage(t)()
+        assertContractNoError(t, store.RecordWin("Pepper"))
+
+        league := store.GetLeague(WholeLeague)
+        league[0].Wins = 100
+
+        assertContractScore(t, store, "Pepper", 1)
+    })
+
+    t.Run("recorded games can be found by ID", func(t *testing.T) {
+        store := newStorage(t)()
+
+        recorded, err := store.RecordGame(contractGame())
+        assertContractNoError(t, err)
+
+        if recorded.ID == "" {
+            t.Fatal("expected the store to give the game an ID")
+        }
+
+        got, found := store.GetGame(recorded.ID)
+
+        if !found {
+            t.Fatalf("game %s not found", recorded.ID)
+        }
+
+        assertContractGame(t, got, recorded)
+    })
+
+    t.Run("recording a game adds a game played for each participant", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("Cleo"))
+        _, err := store.RecordGame(contractGame())
+        assertContractNoError(t, err)
+        _, err = store.RecordGame(contractGame())
+        assertContractNoError(t, err)
+
+        for _, player := range store.GetLeague(WholeLeague) {
+            if player.Played != 2 {
+                t.Errorf("got %s playing %d games, want 2", player.Name, player.Played)
+            }
+        }
+
+        if len(store.GetLeague(WholeLeague)) != 3 {
+            t.Errorf("got %d players in the league, want 3", len(store.GetLeague(WholeLeague)))
+        }
+    })
+
+    t.Run("recording a result records the winner's win and the game together", func(t *testing.T) {
+        store := newStorage(t)()
+
+        recorded, err := store.RecordResult(contractGame())
+        assertContractNoError(t, err)
+
+        assertContractScore(t, store, "Cleo", 1)
+
+        got, found := store.GetGame(recorded.ID)
+
+        if !found {
+            t.Fatalf("game %s not found", recorded.ID)
+        }
+
+        assertContractGame(t, got, recorded)
+    })
+
+    t.Run("games keep the ID they are recorded with", func(t *testing.T) {
+        store := newStorage(t)()
+
+        game := contractGame()
+        game.ID = "table-1"
+
+        recorded, err := store.RecordGame(game)
+        assertContractNoError(t, err)
+
+        if recorded.ID != game.ID {
+            t.Errorf("got ID %q, want %q", recorded.ID, game.ID)
+        }
+    })
+
+    t.Run("unknown games are not found", func(t *testing.T) {
+        store := newStorage(t)()
+
+        if _, found := store.GetGame("nope"); found {
+            t.Error("expected game not to be found")
+        }
+    })
+
+    t.Run("games are listed oldest first", func(t *testing.T) {
+        store := newStorage(t)()
+
+        var want []GameRecord
+        for i := 0; i < 3; i++ {
+            recorded, err := store.RecordGame(contractGame())
+            assertContractNoError(t, err)
+            want = append(want, recorded)
+        }
+
+        got := store.GetGames()
+
+        if len(got) != len(want) {
+            t.Fatalf("got %d games, want %d", len(got), len(want))
+        }
+
+        for i := range want {
+            assertContractGame(t, got[i], want[i])
+        }
+    })
+
+    t.Run("wins and games are kept when the store is reopened", func(t *testing.T) {
+        open := newStorage(t)
+        store := open()
+
+        assertContractNoError(t, store.RecordWin("Pepper"))
+        assertContractNoError(t, store.RecordWin("Pepper"))
+        recorded, err := store.RecordGame(contractGame())
+        assertContractNoError(t, err)
+
+        reopened := open()
+
+        assertContractScore(t, reopened, "Pepper", 2)
+
+        got, found := reopened.GetGame(recorded.ID)
+
+        if !found {
+            t.Fatalf("game %s not found after reopening", recorded.ID)
+        }
+
+        assertContractGame(t, got, recorded)
+    })
+
+    t.Run("recorded games are rated, the same as replaying them", func(t *testing.T) {
+        store := newStorage(t)()
+
+        _, err := store.RecordGame(contractGame())
+        assertContractNoError(t, err)
+        _, err = store.RecordResult(contractGame())
+        assertContractNoError(t, err)
+
+        got := store.GetRatings()
+
+        if got.Get("Cleo").Rating <= InitialRating {
+            t.Errorf("got Cleo rated %v after winning twice, want more than %v", got.Get("Cleo").Rating, InitialRating)
+        }
+
+        assertContractRatings(t, got, RateGames(store.GetGames()))
+    })
+
+    t.Run("ratings follow renamed and merged players and are kept when the store is reopened", func(t *testing.T) {
+        open := newStorage(t)
+        store := open()
+
+        apart := contractGame()
+        apart.Participants = []string{"Cleo", "chris", "Ruth"}
+        apart.FinishingOrder = []string{"chris", "Cleo", "Ruth"}
+        _, err := store.RecordGame(apart)
+        assertContractNoError(t, err)
+
+        together := contractGame()
+        together.Participants = []string{"chris", "Cleo", "Chris", "Ruth"}
+        together.FinishingOrder = []string{"Ruth", "Chris", "chris"}
+        _, err = store.RecordGame(together)
+        assertContractNoError(t, err)
+
+        assertContractNoError(t, store.MergePlayers("chris", "Chris"))
+        assertContractRatings(t, store.GetRatings(), RateGames(store.GetGames()))
+
+        assertContractNoError(t, store.RenamePlayer("Ruth", "Ruthie"))
+        assertContractRatings(t, store.GetRatings(), RateGames(store.GetGames()))
+
+        reopened := open()
+
+        assertContractRatings(t, reopened.GetRatings(), RateGames(reopened.GetGames()))
+
+        if _, found := reopened.GetRatings()["chris"]; found {
+            t.Error("didn't expect a rating for the merged player")
+        }
+    })
+
+    t.Run("renaming a player keeps their wins and the games they played", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("chris"))
+        game := contractGame()
+        game.Participants = []string{"Cleo", "chris", "Ruth"}
+        game.FinishingOrder = []string{"chris", "Cleo", "Ruth"}
+        recorded, err := store.RecordGame(game)
+        assertContractNoError(t, err)
+
+        assertContractNoError(t, store.RenamePlayer("chris", "Chris"))
+
+        if _, found := store.GetPlayer("chris"); found {
+            t.Error("didn't expect to find the player by their old name")
+        }
+
+        if got, found := store.GetPlayer("Chris"); !found || got != (Player{"Chris", 1, 1}) {
+            t.Errorf("got %+v found %v, want Chris with one win from one game", got, found)
+        }
+
+        got, _ := store.GetGame(recorded.ID)
+        recorded.Participants = []string{"Cleo", "Chris", "Ruth"}
+        recorded.FinishingOrder = []string{"Chris", "Cleo", "Ruth"}
+        assertContractGame(t, got, recorded)
+    })
+
+    t.Run("players can't be renamed to nothing or to someone else", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("chris"))
+        assertContractNoError(t, store.RecordWin("Chris"))
+
+        assertContractError(t, store.RenamePlayer("Apollo", "Floyd"), ErrPlayerNotFound)
+        assertContractError(t, store.RenamePlayer("chris", "Chris"), ErrPlayerExists)
+        assertContractError(t, store.RenamePlayer("chris", " "), ErrNoPlayerName)
+
+        assertContractScore(t, store, "chris", 1)
+        assertContractScore(t, store, "Chris", 1)
+    })
+
+    t.Run("merging players adds up their wins and games", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("chris"))
+        assertContractNoError(t, store.RecordWin("Chris"))
+        assertContractNoError(t, store.RecordWin("Chris"))
+
+        apart := contractGame()
+        apart.Participants = []string{"Cleo", "chris", "Ruth"}
+        apart.FinishingOrder = []string{"chris", "Cleo", "Ruth"}
+        _, err := store.RecordGame(apart)
+        assertContractNoError(t, err)
+
+        together := contractGame()
+        together.Participants = []string{"chris", "Cleo", "Chris"}
+        together.FinishingOrder = []string{"Cleo", "Chris", "chris"}
+        recorded, err := store.RecordGame(together)
+        assertContractNoError(t, err)
+
+        assertContractNoError(t, store.MergePlayers("chris", "Chris"))
+
+        if _, found := store.GetPlayer("chris"); found {
+            t.Error("didn't expect to find the merged player")
+        }
+
+        if got, found := store.GetPlayer("Chris"); !found || got != (Player{"Chris", 3, 2}) {
+            t.Errorf("got %+v found %v, want Chris with three wins from two games", got, found)
+        }
+
+        got, _ := store.GetGame(recorded.ID)
+        recorded.Participants = []string{"Chris", "Cleo"}
+        recorded.FinishingOrder = []string{"Cleo", "Chris"}
+        assertContractGame(t, got, recorded)
+    })
+
+    t.Run("merging into someone who isn't in the league renames the player", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("chris"))
+        assertContractNoError(t, store.MergePlayers("chris", "Chris"))
+
+        assertContractLeague(t, store.GetLeague(WholeLeague), League{{"Chris", 1, 0}})
+    })
+
+    t.Run("players can only be merged into someone else", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("Chris"))
+
+        assertContractError(t, store.MergePlayers("Apollo", "Chris"), ErrPlayerNotFound)
+        assertContractError(t, store.MergePlayers("Chris", "Chris"), ErrSamePlayer)
+
+        assertContractScore(t, store, "Chris", 1)
+    })
+
+    t.Run("deleting a player takes them out of the league", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("Pepper"))
+        assertContractNoError(t, store.RecordWin("Floyd"))
+
+        assertContractNoError(t, store.DeletePlayer("Pepper"))
+        assertContractError(t, store.DeletePlayer("Pepper"), ErrPlayerNotFound)
+
+        assertContractLeague(t, store.GetLeague(WholeLeague), League{{"Floyd", 1, 0}})
+    })
+
+    t.Run("changes to players are kept when the store is reopened", func(t *testing.T) {
+        open := newStorage(t)
+        store := open()
+
+        assertContractNoError(t, store.RecordWin("chris"))
+        assertContractNoError(t, store.RecordWin("Cleo"))
+        assertContractNoError(t, store.RecordWin("Ruth"))
+        assertContractNoError(t, store.RenamePlayer("chris", "Chris"))
+        assertContractNoError(t, store.MergePlayers("Ruth", "Cleo"))
+        assertContractNoError(t, store.DeletePlayer("Chris"))
+
+        assertContractLeague(t, open().GetLeague(WholeLeague), League{{"Cleo", 2, 0}})
+    })
+
+    t.Run("the league for a window counts only the wins and games in it", func(t *testing.T) {
+        store := newStorage(t)()
+
+        assertContractNoError(t, store.RecordWin("Pepper"))
+        assertContractNoError(t, store.RecordWin("Pepper"))
+        _, err := store.RecordGame(contractGame())
+        assertContractNoError(t, err)
+
+        now := time.Now()
+        assertContractLeague(t, store.GetLeague(LeagueQuery{Window: Window{now.Add(-time.Hour), now.Add(time.Hour)}}), League{{"Pepper", 2, 0}})
+
+        night := Window{time.Date(2021, 2, 18, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 19, 0, 0, 0, 0, time.UTC)}
+        assertContractLeague(t, store.GetLeague(LeagueQuery{Window: night}), League{{"Cleo", 0, 1}, {"Chris", 0, 1}, {"Ruth", 0, 1}})
+
+        assertContractLeague(t, store.GetLeague(LeagueQuery{Window: Window{To: night.From}}), League{})
+
+        if got, want := store.GetLeagueIn(night), (League{{"Cleo", 0, 1}, {"Chris", 0, 1}, {"Ruth", 0, 1}}); fmt.Sprint(got) != fmt.Sprint(want) {
+            t.Errorf("got league %v for the night, want %v", got, want)
+        }
+        assertContractLeague(t, store.GetLeague(WholeLeague), League{{"Pepper", 2, 0}, {"Cleo", 0, 1}, {"Chris", 0, 1}, {"Ruth", 0, 1}})
+    })
+
+    t.Run("the league for a window follows renamed, merged and deleted players", func(t *testing.T) {
+        open := newStorage(t)
+        store := open()
+
+        for _, name := range []string{"chris", "Chris", "Cleo", "Ruth"} {
+            assertContractNoError(t, store.RecordWin(name))
+        }
+        assertContractNoError(t, store.MergePlayers("chris", "Chris"))
+        assertContractNoError(t, store.RenamePlayer("Cleo", "Cleo Smith"))
+        assertContractNoError(t, store.DeletePlayer("Ruth"))
+
+        now := time.Now()
+        window := Window{now.Add(-time.Hour), now.Add(time.Hour)}
+        assertContractLeague(t, open().GetLeague(LeagueQuery{Window: window}), League{{"Chris", 2, 0}, {"Cleo Smith", 1, 0}})
+    })
+
+    t.Run("the league can be filtered by name prefix, sorted by name and paged", func(t *testing.T) {
+        store := newStorage(t)()
+
+        for _, name := range []string{"Ruth", "Cleo", "Chris", "Chris", "Tiest", "chloe"} {
+            assertContractNoError(t, store.RecordWin(name))
+        }
+
+        assertContractLeague(t, store.GetLeague(LeagueQuery{NamePrefix: "C"}), League{{"Chris", 2, 0}, {"Cleo", 1, 0}})
+        assertContractLeague(t, store.GetLeague(LeagueQuery{Order: ByName}), League{{"Chris", 2, 0}, {"Cleo", 1, 0}, {"Ruth", 1, 0}, {"Tiest", 1, 0}, {"chloe", 1, 0}})
+        assertContractLeague(t, store.GetLeague(LeagueQuery{Offset: 1, Limit: 2}), League{{"Ruth", 1, 0}, {"Cleo", 1, 0}})
+        assertContractLeague(t, store.GetLeague(LeagueQuery{Order: ByName, Offset: 3}), League{{"Tiest", 1, 0}, {"chloe", 1, 0}})
+        assertContractLeague(t, store.GetLeague(LeagueQuery{Offset: 10, Limit: 2}), League{})
+
+        now := time.Now()
+        window := Window{now.Add(-time.Hour), now.Add(time.Hour)}
+        assertContractLeague(t, store.GetLeague(LeagueQuery{Window: window, NamePrefix: "C", Order: ByName, Limit: 1}), League{{"Chris", 2, 0}})
+    })
+
+    t.Run("ties on wins are broken and ranked the same way by every store", func(t *testing.T) {
+        store := newStorage(t)()
+
+        for _, name := range []string{"Ruth", "Cleo", "Tiest", "Chris", "Tiest"} {
+            assertContractNoError(t, store.RecordWin(name))
+        }
+
+        game := contractGame()
+        game.FinishingOrder = []string{"Chris", "Ruth", "Cleo"}
+        _, err := store.RecordGame(game)
+        assertContractNoError(t, err)
+
+        cases := map[TieBreak][]string{
+            TieBreakJoined:     {"Tiest 1", "Ruth 2", "Cleo 2", "Chris 2"},
+            TieBreakName:       {"Tiest 1", "Chris 2", "Cleo 2", "Ruth 2"},
+            TieBreakLastWin:    {"Tiest 1", "Chris 2", "Cleo 3", "Ruth 4"},
+            TieBreakHeadToHead: {"Tiest 1", "Chris 2", "Ruth 3", "Cleo 4"},
+        }
+
+        for tieBreak, want := range cases {
+            assertContractStandings(t, store.GetLeague(LeagueQuery{TieBreak: tieBreak}), want...)
+        }
+
+        assertContractStandings(t, store.GetLeague(WholeLeague), cases[TieBreakJoined]...)
+        assertContractStandings(t, store.GetLeague(LeagueQuery{NamePrefix: "C", Order: ByName}), "Chris 2", "Cleo 2")
+        assertContractStandings(t, store.GetLeague(LeagueQuery{TieBreak: TieBreakHeadToHead, Offset: 2, Limit: 1}), "Ruth 3")
+
+        now := time.Now()
+        window := Window{now.Add(-time.Hour), now.Add(time.Hour)}
+        assertContractStandings(t, store.GetLeague(LeagueQuery{Window: window, TieBreak: TieBreakHeadToHead}), "Tiest 1", "Ruth 2", "Cleo 2", "Chris 2")
+    })
+
+    t.Run("concurrent wins are all counted", func(t *testing.T) {
+        store := newStorage(t)()
+        players := []string{"Pepper", "Floyd"}
+        winsEach := 20
+
+        var wg sync.WaitGroup
+
+        for i := 0; i < winsEach*len(players); i++ {
+            wg.Add(2)
+
+            go func(name string) {
+                defer wg.Done()
+                if err := store.RecordWin(name); err != nil {
+                    t.Error(err)
+                }
+            }(players[i%len(players)])
+
+            go func() {
+                defer wg.Done()
+                store.GetLeague(WholeLeague)
+            }()
+        }
+
+        wg.Wait()
+
+        for _, name := range players {
+            assertContractScore(t, store, name, winsEach)
+        }
+    })
+}
+
+func contractGame() GameRecord {
+    return GameRecord{
+        StartedAt:       time.Date(2021, 2, 18, 20, 0, 0, 0, time.UTC),
+        FinishedAt:      time.Date(2021, 2, 18, 22, 0, 0, 0, time.UTC),
+        NumberOfPlayers: 3,
+        Participants:    []string{"Cleo", "Chris", "Ruth"},
+        FinishingOrder:  []string{"Cleo", "Chris", "Ruth"},
+        BlindStructure:  DefaultBlindStructure.Name,
+    }
+}
+
+func assertContractNoError(t testing.TB, err error) {
+    t.Helper()
+    if err != nil {
+        t.Fatalf("didn't expect an error but got one, %v", err)
+    }
+}
+
+func assertContractError(t testing.TB, got, want error) {
+    t.Helper()
+    if !errors.Is(got, want) {
+        t.Errorf("got error %v, want %v", got, want)
+    }
+}
+
+func assertContractScore(t testing.TB, store PlayerStore, name string, want int) {
+    t.Helper()
+    if got := store.GetPlayerScore(name); got != want {
+        t.Errorf("got %d wins for %s, want %d", got, name, want)
+    }
+}
+
+func assertContractLeague(t testing.TB, got Standings, want League) {
+    t.Helper()
+    if fmt.Sprint(got.Players()) != fmt.Sprint(want) {
+        t.Errorf("got league %v want %v", got.Players(), want)
+    }
+}
+
+// assertContractStandings compares standings written as their name and rank,
+// e.g. "Chris 2".
+func assertContractStandings(t testing.TB, got Standings, want ...string) {
+    t.Helper()
+
+    var ranks []string
+    for _, standing := range got {
+        ranks = append(ranks, fmt.Sprintf("%s %d", standing.Name, standing.Rank))
+    }
+
+    if fmt.Sprint(ranks) != fmt.Sprint(want) {
+        t.Errorf("got standings %v want %v", ranks, want)
+    }
+}
+
+// assertContractRatings compares ratings by their fields' values, as stores
+// may give back times in another location.
+func assertContractRatings(t testing.TB, got, want Ratings) {
+    t.Helper()
+
+    if len(got) != len(want) {
+        t.Fatalf("got %d players rated %v, want %d %v", len(got), got, len(want), want)
+    }
+
+    for name, rating := range want {
+        same := got[name].Name == rating.Name &&
+            got[name].Rating == rating.Rating &&
+            len(got[name].History) == len(rating.History)
+
+        for i := 0; same && i < len(rating.History); i++ {
+            gotChange, wantChange := got[name].History[i], rating.History[i]
+            same = gotChange.GameID == wantChange.GameID &&
+                gotChange.FinishedAt.Equal(wantChange.FinishedAt) &&
+                gotChange.Place == wantChange.Place &&
+                gotChange.Before == wantChange.Before &&
+                gotChange.After == wantChange.After
+        }
+
+        if !same {
+            t.Errorf("got %s rated %+v want %+v", name, got[name], rating)
+        }
+    }
+}
+
+// assertContractGame compares games by their fields' values, as stores may
+// give back times in another location or empty slices as nil.
+func assertContractGame(t testing.TB, got, want GameRecord) {
+    t.Helper()
+
+    same := got.ID == want.ID &&
+        got.StartedAt.Equal(want.StartedAt) &&
+        got.FinishedAt.Equal(want.FinishedAt) &&
+        got.NumberOfPlayers == want.NumberOfPlayers &&
+        fmt.Sprint(got.Participants) == fmt.Sprint(want.Participants) &&
+        fmt.Sprint(got.FinishingOrder) == fmt.Sprint(want.FinishingOrder) &&
+        got.BlindStructure == want.BlindStructure
+
+    if !same {
+        t.Errorf("got game %+v want %+v", got, want)
+    }
+}
@@ -0,0 +1,60 @@
+package poker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSystemPlayerStoreContract(t *testing.T) {
+    TestPlayerStoreContract(t, func(t *testing.T) PlayerStoreOpener {
+        path := filepath.Join(t.TempDir(), "game.db.json")
+
+        return func() PlayerStore {
+            file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+            if err != nil {
+                t.Fatalf("could not open %s %v", path, err)
+            }
+            t.Cleanup(func() { file.Close() })
+
+            store, err := NewFileSystemPlayerStore(file)
+            assertNoError(t, err)
+
+            return store
+        }
+    })
+}
+
+func TestSQLPlayerStoreContract(t *testing.T) {
+    TestPlayerStoreContract(t, func(t *testing.T) PlayerStoreOpener {
+        path := filepath.Join(t.TempDir(), "game.db")
+
+        return func() PlayerStore {
+            return newTestSQLPlayerStore(t, path)
+        }
+    })
+}
+
+func TestInMemoryPlayerStoreContract(t *testing.T) {
+    TestPlayerStoreContract(t, func(t *testing.T) PlayerStoreOpener {
+        var store *InMemoryPlayerStore
+
+        // reopening restores a new store from a snapshot of the last one
+        return func() PlayerStore {
+            if store == nil {
+                store = NewInMemoryPlayerStore()
+                return store
+            }
+
+            snapshot := &bytes.Buffer{}
+            assertNoError(t, store.Snapshot(snapshot))
+
+            restored, err := NewInMemoryPlayerStoreFromSnapshot(snapshot)
+            assertNoError(t, err)
+
+            store = restored
+            return store
+        }
+    })
+}
@@ -0,0 +1,196 @@
+package poker
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+    // InitialRating is the rating of a player who hasn't played a game yet.
+    InitialRating = 1500.0
+    // RatingK is the most a player's rating can move in one game.
+    RatingK = 32.0
+)
+
+// RatingChange is how a player's rating moved in one game. Place is where
+// they finished, or 0 if they weren't placed.
+type RatingChange struct {
+    GameID     string
+    FinishedAt time.Time
+    Place      int
+    Before     float64
+    After      float64
+}
+
+// PlayerRating is a player's Elo rating and how it got there, oldest game
+// first.
+type PlayerRating struct {
+    Name    string
+    Rating  float64
+    History []RatingChange
+}
+
+// Ratings are players' ratings by name.
+type Ratings map[string]PlayerRating
+
+// RateGames works out everyone's rating by replaying games in the order they
+// were played. Player stores keep ratings as games are recorded, see
+// PlayerStore, and replay them with RateGames when merging players changes
+// the games they come from. A window of games is rated by replaying it too.
+//
+// Each game is scored as a match between every pair of its players, won by
+// whoever finished higher. Players who weren't placed tie with each other,
+// below everyone who was. A player's rating moves by RatingK times how much
+// better they did than expected, averaged over their opponents.
+func RateGames(games []GameRecord) Ratings {
+    ratings := Ratings{}
+
+    for _, game := range games {
+        ratings.rate(game)
+    }
+
+    return ratings
+}
+
+// Get returns the rating of the player named name, which is InitialRating if
+// they haven't played.
+func (r Ratings) Get(name string) PlayerRating {
+    if rating, found := r[name]; found {
+        return rating
+    }
+    return PlayerRating{Name: name, Rating: InitialRating, History: []RatingChange{}}
+}
+
+// with returns a copy of the ratings with game rated, leaving r as it was.
+func (r Ratings) with(game GameRecord) Ratings {
+    rated := make(Ratings, len(r))
+
+    for name, rating := range r {
+        rated[name] = rating
+    }
+
+    for _, name := range gamePlayers(game) {
+        if rating, found := rated[name]; found {
+            rating.History = append([]RatingChange{}, rating.History...)
+            rated[name] = rating
+        }
+    }
+
+    rated.rate(game)
+    return rated
+}
+
+// copy returns a copy of the ratings that can be changed without changing r.
+func (r Ratings) copy() Ratings {
+    copied := make(Ratings, len(r))
+
+    for name, rating := range r {
+        rating.History = append([]RatingChange{}, rating.History...)
+        copied[name] = rating
+    }
+
+    return copied
+}
+
+func (r Ratings) rate(game GameRecord) {
+    players := gamePlayers(game)
+
+    if len(players) < 2 || len(game.FinishingOrder) == 0 {
+        return
+    }
+
+    before := make([]float64, len(players))
+    for i, name := range players {
+        before[i] = r.Get(name).Rating
+    }
+
+    for i, name := range players {
+        var surplus float64
+
+        for j, opponent := range players {
+            if i == j {
+                continue
+            }
+            expected := 1 / (1 + math.Pow(10, (before[j]-before[i])/400))
+            surplus += matchScore(game, name, opponent) - expected
+        }
+
+        rating := r.Get(name)
+        rating.Rating = before[i] + RatingK*surplus/float64(len(players)-1)
+        rating.History = append(rating.History, RatingChange{
+            GameID:     game.ID,
+            FinishedAt: game.FinishedAt,
+            Place:      placeIn(game, name),
+            Before:     before[i],
+            After:      rating.Rating,
+        })
+        r[name] = rating
+    }
+}
+
+// matchScore is 1 if player finished above opponent in game, 0 if below and
+// a half if neither was placed.
+func matchScore(game GameRecord, player, opponent string) float64 {
+    unplaced := len(game.FinishingOrder) + 1
+    mine, theirs := placeIn(game, player), placeIn(game, opponent)
+
+    if mine == 0 {
+        mine = unplaced
+    }
+    if theirs == 0 {
+        theirs = unplaced
+    }
+
+    switch {
+    case mine < theirs:
+        return 1
+    case mine > theirs:
+        return 0
+    default:
+        return 0.5
+    }
+}
+
+// placeIn is where name finished in game, counting from 1, or 0 if they
+// weren't placed.
+func placeIn(game GameRecord, name string) int {
+    for i, placed := range game.FinishingOrder {
+        if placed == name {
+            return i + 1
+        }
+    }
+    return 0
+}
+
+// RatedPlayer is a player in the league with their rating and their rank by
+// rating, which players with the same rating share, see Standing.
+type RatedPlayer struct {
+    Player
+    Rating float64
+    Rank   int
+}
+
+// RateLeague rates and ranks everyone in league, highest rating first.
+// Players with the same rating stay in the order they were in the league.
+func RateLeague(league League, ratings Ratings) []RatedPlayer {
+    rated := make([]RatedPlayer, 0, len(league))
+
+    for _, player := range league {
+        rated = append(rated, RatedPlayer{Player: player, Rating: ratings.Get(player.Name).Rating})
+    }
+
+    sort.SliceStable(rated, func(i, j int) bool {
+        return rated[i].Rating > rated[j].Rating
+    })
+
+    for i := range rated {
+        rated[i].Rank = i + 1
+
+        if i > 0 && rated[i].Rating == rated[i-1].Rating {
+            rated[i].Rank = rated[i-1].Rank
+        }
+    }
+
+    return rated
+}
@@ -0,0 +1,100 @@
+package poker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRatings(t *testing.T) {
+    game := func(id string, participants []string, finishingOrder ...string) GameRecord {
+        return GameRecord{ID: id, Participants: participants, FinishingOrder: finishingOrder}
+    }
+
+    t.Run("players start at the initial rating", func(t *testing.T) {
+        got := RateGames(nil).Get("Chris")
+
+        assertRating(t, got.Rating, InitialRating)
+        if len(got.History) != 0 {
+            t.Errorf("got history %v, want none", got.History)
+        }
+    })
+
+    t.Run("the winner of a heads up game between equals takes half of K", func(t *testing.T) {
+        ratings := RateGames([]GameRecord{game("1", []string{"Chris", "Cleo"}, "Cleo", "Chris")})
+
+        assertRating(t, ratings.Get("Cleo").Rating, InitialRating+RatingK/2)
+        assertRating(t, ratings.Get("Chris").Rating, InitialRating-RatingK/2)
+    })
+
+    t.Run("every pair of players is a match won by whoever finished higher", func(t *testing.T) {
+        ratings := RateGames([]GameRecord{game("1", []string{"Chris", "Cleo", "Ruth"}, "Cleo", "Ruth", "Chris")})
+
+        assertRating(t, ratings.Get("Cleo").Rating, 1516)
+        assertRating(t, ratings.Get("Ruth").Rating, 1500)
+        assertRating(t, ratings.Get("Chris").Rating, 1484)
+    })
+
+    t.Run("players who weren't placed tie with each other", func(t *testing.T) {
+        ratings := RateGames([]GameRecord{game("1", []string{"Chris", "Cleo", "Ruth"}, "Cleo")})
+
+        assertRating(t, ratings.Get("Cleo").Rating, 1516)
+        assertRating(t, ratings.Get("Ruth").Rating, 1492)
+        assertRating(t, ratings.Get("Chris").Rating, 1492)
+    })
+
+    t.Run("beating a stronger player is worth more", func(t *testing.T) {
+        ratings := RateGames([]GameRecord{
+            game("1", []string{"Chris", "Cleo"}, "Cleo", "Chris"),
+            game("2", []string{"Chris", "Cleo"}, "Chris", "Cleo"),
+        })
+
+        got := ratings.Get("Chris")
+
+        if len(got.History) != 2 {
+            t.Fatalf("got %d rating changes, want 2", len(got.History))
+        }
+
+        first, second := got.History[0], got.History[1]
+
+        if first.GameID != "1" || first.Place != 2 || second.GameID != "2" || second.Place != 1 {
+            t.Errorf("got history %+v", got.History)
+        }
+
+        assertRating(t, second.Before, first.After)
+        assertRating(t, got.Rating, second.After)
+
+        if gained, lost := second.After-second.Before, first.Before-first.After; gained <= lost {
+            t.Errorf("gained %.2f beating a stronger player, want more than the %.2f lost to an equal", gained, lost)
+        }
+    })
+
+    t.Run("games without a result don't count", func(t *testing.T) {
+        ratings := RateGames([]GameRecord{game("1", []string{"Chris", "Cleo"})})
+
+        if len(ratings) != 0 {
+            t.Errorf("got ratings %v, want none", ratings)
+        }
+    })
+
+    t.Run("rates the league highest first", func(t *testing.T) {
+        league := League{{"Chris", 3, 3}, {"Cleo", 1, 1}, {"Ruth", 0, 0}}
+        ratings := RateGames([]GameRecord{game("1", []string{"Chris", "Cleo"}, "Cleo", "Chris")})
+
+        got := RateLeague(league, ratings)
+
+        want := []string{"Cleo", "Ruth", "Chris"}
+        for i, name := range want {
+            if got[i].Name != name {
+                t.Fatalf("got %+v, want players in order %v", got, want)
+            }
+        }
+        assertRating(t, got[1].Rating, InitialRating)
+    })
+}
+
+func assertRating(t testing.TB, got, want float64) {
+    t.Helper()
+    if math.Abs(got-want) > 0.001 {
+        t.Errorf("got rating %.3f, want %.3f", got, want)
+    }
+}
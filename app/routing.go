@@ -0,0 +1,71 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// APIError is what went wrong with a request, sent as the body of every 4xx
+// and 5xx response in an envelope, e.g.
+// {"error": {"status": 404, "message": "no player named \"Apollo\""}}.
+type APIError struct {
+    Status  int    `json:"status"`
+    Message string `json:"message"`
+}
+
+type errorEnvelope struct {
+    Error APIError `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+    w.Header().Set("content-type", jsonContentType)
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(errorEnvelope{APIError{status, message}})
+}
+
+func notFound(w http.ResponseWriter, r *http.Request) {
+    writeError(w, http.StatusNotFound, fmt.Sprintf("nothing at %s", r.URL.Path))
+}
+
+// methods routes a request to the handler for its method, replying to any
+// other method with 405 and the methods that are allowed.
+type methods map[string]http.HandlerFunc
+
+func (m methods) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    if handler, ok := m[r.Method]; ok {
+        handler(w, r)
+        return
+    }
+
+    w.Header().Set("allow", m.allowed())
+    writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not allowed, use %s", r.Method, m.allowed()))
+}
+
+func (m methods) allowed() string {
+    var allowed []string
+    for method := range m {
+        allowed = append(allowed, method)
+    }
+    sort.Strings(allowed)
+    return strings.Join(allowed, ", ")
+}
+
+// pathParams splits the rest of r's path after prefix into URL-decoded
+// segments, so "/players/AC%2FDC" after "/players/" is the one name "AC/DC".
+func pathParams(r *http.Request, prefix string) ([]string, error) {
+    segments := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), prefix), "/")
+
+    for i, segment := range segments {
+        decoded, err := url.PathUnescape(segment)
+        if err != nil {
+            return nil, fmt.Errorf("problem decoding %q, %v", segment, err)
+        }
+        segments[i] = decoded
+    }
+
+    return segments, nil
+}
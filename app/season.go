@@ -0,0 +1,215 @@
+package poker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+    ErrUnknownSeason = errors.New("unknown season")
+    ErrBadSeason     = errors.New("bad season")
+    ErrBadWindow     = errors.New("bad time window")
+)
+
+// Window is a span of time from From up to, but not including, To. A zero
+// From or To leaves that end of the window open.
+type Window struct {
+    From time.Time
+    To   time.Time
+}
+
+// AllTime is the window every time is in.
+var AllTime = Window{}
+
+func (w Window) IsAllTime() bool {
+    return w.From.IsZero() && w.To.IsZero()
+}
+
+func (w Window) Contains(t time.Time) bool {
+    if !w.From.IsZero() && t.Before(w.From) {
+        return false
+    }
+    if !w.To.IsZero() && !t.Before(w.To) {
+        return false
+    }
+    return true
+}
+
+// gamesIn returns the games that finished in window.
+func gamesIn(games []GameRecord, window Window) []GameRecord {
+    in := []GameRecord{}
+
+    for _, game := range games {
+        if window.Contains(game.FinishedAt) {
+            in = append(in, game)
+        }
+    }
+
+    return in
+}
+
+// ParseWindow reads a window from its ends, either of which can be empty to
+// leave it open. Each end is a date, e.g. "2026-07-01", meaning midnight UTC,
+// or an RFC 3339 time.
+func ParseWindow(from, to string) (Window, error) {
+    var window Window
+    var err error
+
+    if window.From, err = parseWindowEnd(from); err != nil {
+        return Window{}, fmt.Errorf("%w, from %v", ErrBadWindow, err)
+    }
+
+    if window.To, err = parseWindowEnd(to); err != nil {
+        return Window{}, fmt.Errorf("%w, to %v", ErrBadWindow, err)
+    }
+
+    if !window.From.IsZero() && !window.To.IsZero() && !window.From.Before(window.To) {
+        return Window{}, fmt.Errorf("%w, from %s isn't before to %s", ErrBadWindow, from, to)
+    }
+
+    return window, nil
+}
+
+func parseWindowEnd(end string) (time.Time, error) {
+    if end == "" {
+        return time.Time{}, nil
+    }
+
+    if t, err := time.Parse("2006-01-02", end); err == nil {
+        return t, nil
+    }
+
+    t, err := time.Parse(time.RFC3339, end)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("%q is not a date or RFC 3339 time", end)
+    }
+
+    return t, nil
+}
+
+// Season is a named stretch of time the league is reset for, from Start up
+// to, but not including, End.
+type Season struct {
+    Name  string
+    Start time.Time
+    End   time.Time
+}
+
+func (s Season) Window() Window {
+    return Window{s.Start, s.End}
+}
+
+var quarterName = regexp.MustCompile(`^(\d{4})-Q([1-4])$`)
+
+// QuarterSeason returns the season for a quarter of a year, named like
+// "2026-Q3", which runs from the first of July to the first of October UTC.
+func QuarterSeason(name string) (Season, error) {
+    match := quarterName.FindStringSubmatch(name)
+
+    if match == nil {
+        return Season{}, fmt.Errorf("%w %q, want a season like 2026-Q3", ErrUnknownSeason, name)
+    }
+
+    year, _ := strconv.Atoi(match[1])
+    quarter, _ := strconv.Atoi(match[2])
+
+    start := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+
+    return Season{Name: name, Start: start, End: start.AddDate(0, 3, 0)}, nil
+}
+
+// seasonConfig is how a season is written in a file, with its dates written
+// as for ParseWindow.
+type seasonConfig struct {
+    Name  string `json:"name" yaml:"name"`
+    Start string `json:"start" yaml:"start"`
+    End   string `json:"end" yaml:"end"`
+}
+
+func (c seasonConfig) season() (Season, error) {
+    if c.Name == "" {
+        return Season{}, fmt.Errorf("%w, a season has no name", ErrBadSeason)
+    }
+
+    if c.Start == "" || c.End == "" {
+        return Season{}, fmt.Errorf("%w, season %q needs a start and an end", ErrBadSeason, c.Name)
+    }
+
+    window, err := ParseWindow(c.Start, c.End)
+    if err != nil {
+        return Season{}, fmt.Errorf("%w %q, %v", ErrBadSeason, c.Name, err)
+    }
+
+    return Season{c.Name, window.From, window.To}, nil
+}
+
+func seasonsFromConfig(configs []seasonConfig) ([]Season, error) {
+    seasons := make([]Season, 0, len(configs))
+
+    for _, config := range configs {
+        season, err := config.season()
+        if err != nil {
+            return nil, err
+        }
+        seasons = append(seasons, season)
+    }
+
+    return seasons, nil
+}
+
+// NewSeasons reads seasons written as a JSON array, e.g.
+// [{"name": "spring", "start": "2026-03-01", "end": "2026-06-01"}].
+func NewSeasons(rdr io.Reader) ([]Season, error) {
+    var configs []seasonConfig
+
+    if err := json.NewDecoder(rdr).Decode(&configs); err != nil {
+        return nil, fmt.Errorf("problem parsing seasons, %v", err)
+    }
+
+    return seasonsFromConfig(configs)
+}
+
+// NewSeasonsFromYAML reads seasons written as a YAML list.
+func NewSeasonsFromYAML(rdr io.Reader) ([]Season, error) {
+    var configs []seasonConfig
+
+    if err := yaml.NewDecoder(rdr).Decode(&configs); err != nil {
+        return nil, fmt.Errorf("problem parsing seasons, %v", err)
+    }
+
+    return seasonsFromConfig(configs)
+}
+
+// SeasonsFromFile loads seasons from a .json, .yaml or .yml file.
+func SeasonsFromFile(path string) ([]Season, error) {
+    file, err := os.Open(path)
+
+    if err != nil {
+        return nil, fmt.Errorf("problem opening %s %v", path, err)
+    }
+    defer file.Close()
+
+    var seasons []Season
+
+    switch ext := filepath.Ext(path); ext {
+    case ".yaml", ".yml":
+        seasons, err = NewSeasonsFromYAML(file)
+    default:
+        seasons, err = NewSeasons(file)
+    }
+
+    if err != nil {
+        return nil, fmt.Errorf("problem loading seasons from file %s, %v", path, err)
+    }
+
+    return seasons, nil
+}
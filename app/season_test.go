@@ -0,0 +1,114 @@
+package poker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSeasons(t *testing.T) {
+    date := func(year int, month time.Month, day int) time.Time {
+        return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+    }
+
+    t.Run("quarters run for three months", func(t *testing.T) {
+        got, err := QuarterSeason("2026-Q3")
+        assertNoError(t, err)
+
+        want := Season{"2026-Q3", date(2026, time.July, 1), date(2026, time.October, 1)}
+        if got != want {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+
+        fourth, _ := QuarterSeason("2026-Q4")
+        if want := date(2027, time.January, 1); !fourth.End.Equal(want) {
+            t.Errorf("got the fourth quarter ending %v, want %v", fourth.End, want)
+        }
+    })
+
+    t.Run("rejects seasons that aren't quarters", func(t *testing.T) {
+        for _, name := range []string{"2026-Q5", "2026", "Q3", "spring"} {
+            if _, err := QuarterSeason(name); !errors.Is(err, ErrUnknownSeason) {
+                t.Errorf("got error %v for %q, want %v", err, name, ErrUnknownSeason)
+            }
+        }
+    })
+
+    t.Run("windows include their start but not their end", func(t *testing.T) {
+        window := Window{date(2026, time.July, 1), date(2026, time.October, 1)}
+
+        cases := map[time.Time]bool{
+            date(2026, time.June, 30):                         false,
+            date(2026, time.July, 1):                          true,
+            date(2026, time.September, 30):                    true,
+            date(2026, time.October, 1):                       false,
+            date(2026, time.October, 1).Add(-time.Nanosecond): true,
+        }
+
+        for at, want := range cases {
+            if got := window.Contains(at); got != want {
+                t.Errorf("got %v for %v, want %v", got, at, want)
+            }
+        }
+    })
+
+    t.Run("windows can be open at either end", func(t *testing.T) {
+        window, err := ParseWindow("2026-07-01", "")
+        assertNoError(t, err)
+
+        if !window.Contains(date(2100, time.January, 1)) || window.Contains(date(2026, time.June, 1)) {
+            t.Errorf("got window %+v, want it open after the first of July", window)
+        }
+
+        if !AllTime.Contains(time.Time{}) {
+            t.Error("expected all time to contain every time")
+        }
+    })
+
+    t.Run("reads window ends as dates or times", func(t *testing.T) {
+        window, err := ParseWindow("2026-07-01", "2026-07-01T20:30:00+01:00")
+        assertNoError(t, err)
+
+        if want := time.Date(2026, time.July, 1, 19, 30, 0, 0, time.UTC); !window.To.Equal(want) {
+            t.Errorf("got to %v, want %v", window.To, want)
+        }
+    })
+
+    t.Run("rejects bad windows", func(t *testing.T) {
+        for _, ends := range [][2]string{{"yesterday", ""}, {"", "2026-13-01"}, {"2026-10-01", "2026-07-01"}, {"2026-07-01", "2026-07-01"}} {
+            if _, err := ParseWindow(ends[0], ends[1]); !errors.Is(err, ErrBadWindow) {
+                t.Errorf("got error %v for %v, want %v", err, ends, ErrBadWindow)
+            }
+        }
+    })
+
+    t.Run("loads named seasons from YAML", func(t *testing.T) {
+        path := filepath.Join(t.TempDir(), "seasons.yaml")
+        os.WriteFile(path, []byte("- name: spring\n  start: 2026-03-01\n  end: 2026-06-01\n"), 0666)
+
+        got, err := SeasonsFromFile(path)
+        assertNoError(t, err)
+
+        want := []Season{{"spring", date(2026, time.March, 1), date(2026, time.June, 1)}}
+        if len(got) != 1 || got[0] != want[0] {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+
+    t.Run("rejects seasons without a name or dates", func(t *testing.T) {
+        cases := []string{
+            `[{"start": "2026-03-01", "end": "2026-06-01"}]`,
+            `[{"name": "spring", "start": "2026-03-01"}]`,
+            `[{"name": "spring", "start": "2026-06-01", "end": "2026-03-01"}]`,
+        }
+
+        for _, config := range cases {
+            if _, err := NewSeasons(strings.NewReader(config)); !errors.Is(err, ErrBadSeason) {
+                t.Errorf("got error %v for %s, want %v", err, config, ErrBadSeason)
+            }
+        }
+    })
+}
@@ -1,12 +1,17 @@
 package poker
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -18,10 +23,19 @@ type PlayerServer struct {
     store PlayerStore
 	http.Handler
     template *template.Template
-    game Game
+    games *GameRegistry
+    blindStructures map[string]BlindStructure
+    seasons map[string]Season
+    tieBreak TieBreak
+    hub *Hub
+    sessions *gameSessions
+    resumeTimeout time.Duration
+    gameExpiry time.Duration
 }
 
-func NewPlayerServer(store PlayerStore, game Game) (*PlayerServer, error) {
+// NewPlayerServer serves the league from store. Each game started over /ws is
+// a new game made by newGame, so several tables can play at once.
+func NewPlayerServer(store PlayerStore, newGame func() Game) (*PlayerServer, error) {
     p := new (PlayerServer)
 
     tmpl, err := template.ParseFiles(htmlTemplatePath)
@@ -32,32 +46,487 @@ func NewPlayerServer(store PlayerStore, game Game) (*PlayerServer, error) {
 
     p.template = tmpl
 	p.store = store
-    p.game = game
+    p.games = NewGameRegistry(newGame)
+    p.blindStructures = make(map[string]BlindStructure)
+    p.seasons = make(map[string]Season)
+    p.hub = NewHub()
+    p.sessions = newGameSessions()
+    p.resumeTimeout = DefaultResumeTimeout
+    p.gameExpiry = DefaultGameExpiry
+
+    for _, blinds := range BlindStructurePresets {
+        p.RegisterBlindStructure(blinds)
+    }
 
 	router := http.NewServeMux()
-    router.Handle("/league", http.HandlerFunc(p.leagueHandler))
+    router.Handle("/", http.HandlerFunc(notFound))
+    router.Handle("/league", methods{http.MethodGet: p.leagueHandler})
     router.Handle("/players/", http.HandlerFunc(p.playersHandler))
-    router.Handle("/game", http.HandlerFunc(p.gameHandler))
-    router.Handle("/ws", http.HandlerFunc(p.webSocketHandler))
+    router.Handle("/games", methods{http.MethodGet: p.gamesHandler, http.MethodPost: p.startGame})
+    router.Handle("/games/", http.HandlerFunc(p.gameRoutes))
+    router.Handle("/games/active", methods{http.MethodGet: p.activeGamesHandler})
+    router.Handle("/game", methods{http.MethodGet: p.gameHandler})
+    router.Handle("/ws", methods{http.MethodGet: p.webSocketHandler})
 
 	p.Handler = router
 
     return p, nil
 }
 
+// RegisterBlindStructure makes blinds available to games started over the
+// WebSocket under its name.
+func (p *PlayerServer) RegisterBlindStructure(blinds BlindStructure) {
+    p.blindStructures[blinds.Name] = blinds
+}
+
+// RegisterSeason makes the league for season available under its name, as
+// well as the quarters every server has, see QuarterSeason.
+func (p *PlayerServer) RegisterSeason(season Season) {
+    p.seasons[season.Name] = season
+}
+
+// BreakTiesBy orders players level on wins in the league by tieBreak, in the
+// order they joined it if it's never called.
+func (p *PlayerServer) BreakTiesBy(tieBreak TieBreak) {
+    p.tieBreak = tieBreak
+}
+
+func (p *PlayerServer) lookupSeason(name string) (Season, error) {
+    if season, found := p.seasons[name]; found {
+        return season, nil
+    }
+    return QuarterSeason(name)
+}
+
+// Orders GET /league?sort= can be in, by wins by default.
+const (
+    sortByWins   = "wins"
+    sortByName   = "name"
+    sortByRating = "rating"
+)
+
+// leagueHandler replies with the all-time league, or with ?season=2026-Q3 or
+// ?from=2026-07-01&to=2026-10-01 the league for that window of time. With
+// ?sort=name it is sorted by name, and with ?sort=rating it is rated and
+// ordered by rating, see RateGames, rating only the games in the window.
+// ?prefix= keeps only players whose names start with it, and ?limit= and
+// ?offset= page through it, with a Link header to the other pages. Everyone
+// has a Rank, their place in the whole league by wins, or by rating when
+// sorted by rating, with ties broken as set by BreakTiesBy.
 func (p *PlayerServer) leagueHandler(w http.ResponseWriter, r *http.Request) {
+    params := r.URL.Query()
+
+    window, err := p.leagueWindow(params)
+    if err != nil {
+        writeError(w, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    offset, limit, err := leaguePage(params)
+    if err != nil {
+        writeError(w, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    query := LeagueQuery{Window: window, TieBreak: p.tieBreak}
+    prefix := params.Get("prefix")
+
+    var players interface{}
+    var more bool
+
+    switch order := params.Get("sort"); order {
+    case "", sortByWins, sortByName:
+        query.NamePrefix = prefix
+        query.Order = LeagueOrder(order)
+        query.Offset = offset
+
+        // ask for one more than the page to know if there's a next page
+        if limit > 0 {
+            query.Limit = limit + 1
+        }
+
+        league := p.store.GetLeague(query)
+        if more = limit > 0 && len(league) > limit; more {
+            league = league[:limit]
+        }
+        players = league
+    case sortByRating:
+        rated := []RatedPlayer{}
+
+        ratings := p.store.GetRatings()
+        if !window.IsAllTime() {
+            ratings = RateGames(gamesIn(p.store.GetGames(), window))
+        }
+
+        // rate the whole league so that everyone keeps their rank in it
+        for _, player := range RateLeague(p.store.GetLeague(query).Players(), ratings) {
+            if strings.HasPrefix(player.Name, prefix) {
+                rated = append(rated, player)
+            }
+        }
+
+        if offset > len(rated) {
+            offset = len(rated)
+        }
+        rated = rated[offset:]
+
+        if more = limit > 0 && len(rated) > limit; more {
+            rated = rated[:limit]
+        }
+        players = rated
+    default:
+        writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown sort %q, want %s, %s or %s", order, sortByWins, sortByName, sortByRating))
+        return
+    }
+
+    if limit > 0 {
+        w.Header().Set("Link", leagueLinks(r.URL, offset, limit, more))
+    }
+
+    w.Header().Set("content-type", jsonContentType)
+    json.NewEncoder(w).Encode(players)
+}
+
+// leaguePage reads the ?offset= and ?limit= of a page of the league. Without
+// a limit the page is the rest of the league.
+func leaguePage(params url.Values) (offset, limit int, err error) {
+    if value := params.Get("offset"); value != "" {
+        if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+            return 0, 0, fmt.Errorf("offset %q isn't a number of players to skip", value)
+        }
+    }
+
+    if value := params.Get("limit"); value != "" {
+        if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+            return 0, 0, fmt.Errorf("limit %q isn't a number of players greater than 0", value)
+        }
+    }
+
+    return offset, limit, nil
+}
+
+// leagueLinks is the Link header for the page of the league limit players
+// long at offset, linking to the first page, the previous page if there is
+// one and the next page if there's more. The links keep the rest of the
+// query.
+func leagueLinks(page *url.URL, offset, limit int, more bool) string {
+    link := func(offset int, rel string) string {
+        params := page.Query()
+        params.Set("offset", strconv.Itoa(offset))
+        params.Set("limit", strconv.Itoa(limit))
+        return fmt.Sprintf(`<%s?%s>; rel="%s"`, page.Path, params.Encode(), rel)
+    }
+
+    links := []string{link(0, "first")}
+
+    if offset > 0 {
+        previous := offset - limit
+        if previous < 0 {
+            previous = 0
+        }
+        links = append(links, link(previous, "prev"))
+    }
+
+    if more {
+        links = append(links, link(offset+limit, "next"))
+    }
+
+    return strings.Join(links, ", ")
+}
+
+// leagueWindow is the window of time the league is asked for in, AllTime if
+// it isn't asked for one.
+func (p *PlayerServer) leagueWindow(params url.Values) (Window, error) {
+    name, from, to := params.Get("season"), params.Get("from"), params.Get("to")
+
+    switch {
+    case name != "" && (from != "" || to != ""):
+        return AllTime, fmt.Errorf("%w, ask for a season or for from and to, not both", ErrBadWindow)
+    case name != "":
+        season, err := p.lookupSeason(name)
+        return season.Window(), err
+    case from != "" || to != "":
+        return ParseWindow(from, to)
+    default:
+        return AllTime, nil
+    }
+}
+
+func (p *PlayerServer) gamesHandler(w http.ResponseWriter, r *http.Request) {
+    games := p.store.GetGames()
+
+    if games == nil {
+        games = []GameRecord{}
+    }
+
+    w.Header().Set("content-type", jsonContentType)
+    json.NewEncoder(w).Encode(games)
+}
+
+func (p *PlayerServer) activeGamesHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("content-type", jsonContentType)
+    json.NewEncoder(w).Encode(p.games.Active())
+}
+
+// gameRoutes routes /games/{id}, /games/{id}/finish and /games/{id}/watch.
+func (p *PlayerServer) gameRoutes(w http.ResponseWriter, r *http.Request) {
+    params, err := pathParams(r, "/games/")
+
+    if err != nil {
+        writeError(w, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    id := params[0]
+
+    switch {
+    case id == "":
+        notFound(w, r)
+    case len(params) == 1:
+        methods{
+            http.MethodGet: func(w http.ResponseWriter, r *http.Request) { p.showGame(w, id) },
+        }.ServeHTTP(w, r)
+    case len(params) == 2 && params[1] == "finish":
+        methods{
+            http.MethodPost: func(w http.ResponseWriter, r *http.Request) { p.finishGameHandler(w, r, id) },
+        }.ServeHTTP(w, r)
+    case len(params) == 2 && params[1] == "watch":
+        methods{
+            http.MethodGet: func(w http.ResponseWriter, r *http.Request) { p.watchHandler(w, r, id) },
+        }.ServeHTTP(w, r)
+    default:
+        notFound(w, r)
+    }
+}
+
+func (p *PlayerServer) showGame(w http.ResponseWriter, id string) {
+    // games being played show where their clock is at, finished ones their
+    // result
+    if active, found := p.games.Describe(id); found {
+        w.Header().Set("content-type", jsonContentType)
+        json.NewEncoder(w).Encode(active)
+        return
+    }
+
+    game, found := p.store.GetGame(id)
+
+    if !found {
+        writeError(w, http.StatusNotFound, fmt.Sprintf("no game %q", id))
+        return
+    }
+
+    w.Header().Set("content-type", jsonContentType)
+    json.NewEncoder(w).Encode(game)
+}
+
+// startGame starts a game at a new table from a roster posted as JSON, e.g.
+// {"players": ["Ruth", "Chris", "Cleo"], "blindStructure": "turbo"}, for
+// clients that don't use the WebSocket. Its blind alerts go to spectators,
+// and it is abandoned if it hasn't been finished within the game expiry.
+func (p *PlayerServer) startGame(w http.ResponseWriter, r *http.Request) {
+    var start StartPayload
+
+    if err := json.NewDecoder(r.Body).Decode(&start); err != nil {
+        writeError(w, http.StatusBadRequest, fmt.Sprintf("problem parsing game, %v", err))
+        return
+    }
+
+    players, blinds, err := p.checkStart(start)
+    if err != nil {
+        writeError(w, http.StatusBadRequest, err.Error())
+        return
+    }
+
+    session := p.startSession(nil, players, blinds)
+    session.expire(p.gameExpiry, func() { p.endSession(session) })
+    active, _ := p.games.Describe(session.id)
+
+    w.Header().Set("content-type", jsonContentType)
+    w.Header().Set("location", "/games/"+session.id)
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(active)
+}
+
+// finishGameHandler declares the result of the game with id from JSON, e.g.
+// {"winner": "Ruth", "placings": ["Chris", "Cleo"]}, replying with the
+// game's record.
+func (p *PlayerServer) finishGameHandler(w http.ResponseWriter, r *http.Request, id string) {
+    session, found := p.sessions.forGame(id)
+
+    if !found {
+        writeError(w, http.StatusNotFound, fmt.Sprintf("no game %q running", id))
+        return
+    }
+
+    var result FinishPayload
+
+    if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+        writeError(w, http.StatusBadRequest, fmt.Sprintf("problem parsing result, %v", err))
+        return
+    }
+
+    err := p.finish(session, result)
+
+    switch {
+    case isBadResult(err):
+        writeError(w, http.StatusBadRequest, err.Error())
+        return
+    case errors.Is(err, ErrNoGameRunning):
+        writeError(w, http.StatusNotFound, fmt.Sprintf("no game %q running", id))
+        return
+    case err != nil:
+        log.Printf("problem finishing game %s %v\n", id, err)
+        writeError(w, http.StatusInternalServerError, "problem recording the result")
+        return
+    }
+
+    record, found := p.store.GetGame(id)
+
+    if !found {
+        w.WriteHeader(http.StatusNoContent)
+        return
+    }
+
     w.Header().Set("content-type", jsonContentType)
-    json.NewEncoder(w).Encode(p.store.GetLeague())
+    json.NewEncoder(w).Encode(record)
 }
 
+// playersHandler routes /players/{name} and the admin routes below it, where
+// name is URL-encoded.
 func (p *PlayerServer) playersHandler(w http.ResponseWriter, r *http.Request) {
-    player := r.URL.Path[len("/players/"):]
+    params, err := pathParams(r, "/players/")
+
+    if err != nil {
+        writeError(w, http.StatusBadRequest, err.Error())
+        return
+    }
 
-    switch r.Method {
-    case http.MethodPost:
-        p.processWin(w, player)
-    case http.MethodGet:
-        p.showScore(w, player)
+    player := params[0]
+
+    switch {
+    case player == "":
+        notFound(w, r)
+    case len(params) == 1:
+        methods{
+            http.MethodGet:    func(w http.ResponseWriter, r *http.Request) { p.showScore(w, r, player) },
+            http.MethodPost:   func(w http.ResponseWriter, r *http.Request) { p.processWin(w, player) },
+            http.MethodDelete: func(w http.ResponseWriter, r *http.Request) { p.deletePlayer(w, player) },
+        }.ServeHTTP(w, r)
+    case len(params) == 2 && params[1] == "stats":
+        methods{
+            http.MethodGet: func(w http.ResponseWriter, r *http.Request) { p.showStats(w, player) },
+        }.ServeHTTP(w, r)
+    case len(params) == 2 && params[1] == "rating":
+        methods{
+            http.MethodGet: func(w http.ResponseWriter, r *http.Request) { p.showRating(w, player) },
+        }.ServeHTTP(w, r)
+    case len(params) == 2 && params[1] == "rename":
+        methods{
+            http.MethodPost: func(w http.ResponseWriter, r *http.Request) { p.renamePlayer(w, r, player) },
+        }.ServeHTTP(w, r)
+    case len(params) == 2 && params[1] == "merge":
+        methods{
+            http.MethodPost: func(w http.ResponseWriter, r *http.Request) { p.mergePlayers(w, r, player) },
+        }.ServeHTTP(w, r)
+    default:
+        notFound(w, r)
+    }
+}
+
+// showStats replies with the player's stats from the games recorded, see
+// StatsFor.
+func (p *PlayerServer) showStats(w http.ResponseWriter, name string) {
+    if _, found := p.store.GetPlayer(name); !found {
+        writeError(w, http.StatusNotFound, fmt.Sprintf("no player named %q", name))
+        return
+    }
+
+    w.Header().Set("content-type", jsonContentType)
+    json.NewEncoder(w).Encode(StatsFor(name, p.store.GetGames()))
+}
+
+// showRating replies with the player's rating and how it has moved over the
+// games they played.
+func (p *PlayerServer) showRating(w http.ResponseWriter, name string) {
+    if _, found := p.store.GetPlayer(name); !found {
+        writeError(w, http.StatusNotFound, fmt.Sprintf("no player named %q", name))
+        return
+    }
+
+    w.Header().Set("content-type", jsonContentType)
+    json.NewEncoder(w).Encode(p.store.GetRatings().Get(name))
+}
+
+type renameRequest struct {
+    Name string `json:"name"`
+}
+
+type mergeRequest struct {
+    Into string `json:"into"`
+}
+
+// renamePlayer renames a player from JSON, e.g. {"name": "Chris"}, replying
+// with the renamed player.
+func (p *PlayerServer) renamePlayer(w http.ResponseWriter, r *http.Request, name string) {
+    var rename renameRequest
+
+    if err := json.NewDecoder(r.Body).Decode(&rename); err != nil {
+        writeError(w, http.StatusBadRequest, fmt.Sprintf("problem parsing rename, %v", err))
+        return
+    }
+
+    err := p.store.RenamePlayer(name, rename.Name)
+    p.replyWithPlayer(w, rename.Name, err)
+}
+
+// mergePlayers merges a player into the one named in JSON, e.g.
+// {"into": "Chris"}, replying with the merged player.
+func (p *PlayerServer) mergePlayers(w http.ResponseWriter, r *http.Request, name string) {
+    var merge mergeRequest
+
+    if err := json.NewDecoder(r.Body).Decode(&merge); err != nil {
+        writeError(w, http.StatusBadRequest, fmt.Sprintf("problem parsing merge, %v", err))
+        return
+    }
+
+    err := p.store.MergePlayers(name, merge.Into)
+    p.replyWithPlayer(w, merge.Into, err)
+}
+
+func (p *PlayerServer) deletePlayer(w http.ResponseWriter, name string) {
+    if err := p.store.DeletePlayer(name); err != nil {
+        writePlayerError(w, err)
+        return
+    }
+
+    w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *PlayerServer) replyWithPlayer(w http.ResponseWriter, name string, err error) {
+    if err != nil {
+        writePlayerError(w, err)
+        return
+    }
+
+    player, _ := p.store.GetPlayer(name)
+
+    w.Header().Set("content-type", jsonContentType)
+    json.NewEncoder(w).Encode(player)
+}
+
+// writePlayerError replies to a request to change a player that the store
+// refused.
+func writePlayerError(w http.ResponseWriter, err error) {
+    switch {
+    case errors.Is(err, ErrPlayerNotFound):
+        writeError(w, http.StatusNotFound, err.Error())
+    case errors.Is(err, ErrPlayerExists):
+        writeError(w, http.StatusConflict, err.Error())
+    case errors.Is(err, ErrSamePlayer), errors.Is(err, ErrNoPlayerName):
+        writeError(w, http.StatusBadRequest, err.Error())
+    default:
+        log.Printf("problem changing player %v\n", err)
+        writeError(w, http.StatusInternalServerError, "problem changing the player")
     }
 }
 
@@ -71,67 +540,381 @@ var wsUpgrader = websocket.Upgrader{
 }
 
 func (p *PlayerServer) webSocketHandler(w http.ResponseWriter, r *http.Request) {
-    ws := newPlayerServerWS(w, r)
+    ws, err := newPlayerServerWS(w, r)
+    if err != nil {
+        return
+    }
+    defer ws.Close()
 
-    numberOfPlayersMsg := ws.WaitForMsg()
-    numberOfPlayers, _ := strconv.Atoi(numberOfPlayersMsg)
-    p.game.Start(numberOfPlayers, ws)
+    session, err := p.waitForSession(ws)
+    if err != nil {
+        return
+    }
+
+    // losing the connection leaves the game running for a while so the
+    // dealer can reconnect and resume it
+    defer session.detach(ws, p.resumeTimeout, func() { p.endSession(session) })
+
+    id, game := session.id, session.game
+
+    ws.Send(SessionMessage, SessionPayload{GameID: id, Token: session.token})
+    p.sendClockState(ws, game, id)
+
+    for {
+        msg, err := ws.Receive()
+
+        if errors.Is(err, ErrBadMessage) {
+            ws.SendError(err)
+            continue
+        }
+
+        if err != nil {
+            return
+        }
+
+        switch msg.Type {
+        case PauseMessage:
+            var pause PausePayload
+            if err := msg.DecodePayload(&pause); err != nil {
+                ws.SendError(err)
+                continue
+            }
+
+            cmd := ResumeClock
+            if pause.Paused {
+                cmd = PauseClock
+            }
+            p.controlClock(ws, game, id, cmd)
+
+        case ClockMessage:
+            var clock ClockPayload
+            if err := msg.DecodePayload(&clock); err != nil {
+                ws.SendError(err)
+                continue
+            }
+
+            cmd, ok := ClockCommands[clock.Command]
+            if !ok {
+                ws.SendError(fmt.Errorf("%w %q", ErrUnknownCommand, clock.Command))
+                continue
+            }
+            p.controlClock(ws, game, id, cmd)
+
+        case FinishMessage:
+            if done := p.finishGame(ws, session, msg); done {
+                return
+            }
+
+        default:
+            ws.SendError(fmt.Errorf("%w, %s while a game is running", ErrUnexpectedMessage, msg.Type))
+        }
+    }
+}
 
-    winner := ws.WaitForMsg()
-    p.game.Finish(winner)
+// waitForSession reads messages until one starts a game or resumes one,
+// replying with an error to any that don't, and returns the session with ws
+// attached. It only fails if the connection does.
+func (p *PlayerServer) waitForSession(ws *playerServerWS) (*gameSession, error) {
+    for {
+        msg, err := ws.Receive()
+
+        if errors.Is(err, ErrBadMessage) {
+            ws.SendError(err)
+            continue
+        }
+
+        if err != nil {
+            return nil, err
+        }
+
+        switch msg.Type {
+        case StartMessage:
+            players, blinds, err := p.parseStart(msg)
+            if err != nil {
+                ws.SendError(err)
+                continue
+            }
+
+            return p.startSession(ws, players, blinds), nil
+
+        case ResumeMessage:
+            var resume ResumePayload
+            if err := msg.DecodePayload(&resume); err != nil {
+                ws.SendError(err)
+                continue
+            }
+
+            session, found := p.sessions.get(resume.Token)
+            if !found {
+                ws.SendError(ErrUnknownSession)
+                continue
+            }
+
+            session.attach(ws)
+            return session, nil
+
+        default:
+            ws.SendError(fmt.Errorf("%w, %s before the game has started", ErrUnexpectedMessage, msg.Type))
+        }
+    }
 }
 
-func (p *PlayerServer) showScore(w http.ResponseWriter, player string) {
-    score := p.store.GetPlayerScore(player)
+// startSession starts a game at a new table dealt from ws. The game runs
+// until it finishes or its dealer has been gone too long to resume it.
+func (p *PlayerServer) startSession(ws *playerServerWS, players []string, blinds BlindStructure) *gameSession {
+    ctx, cancel := context.WithCancel(context.Background())
+    session := &gameSession{token: newSessionToken(), cancel: cancel, ws: ws}
 
-    if score == 0 {
-        w.WriteHeader(http.StatusNotFound)
+    // spectators get the blind alerts too, from the opening level on
+    feed := &gameFeed{hub: p.hub}
+    id, game := p.games.Start(ctx, players, blinds, blindAlertWriters{feed, session})
+    feed.setID(id)
+
+    session.id, session.game = id, game
+    p.sessions.add(session)
+
+    return session
+}
+
+func (p *PlayerServer) endSession(session *gameSession) {
+    if !session.end() {
+        return
     }
 
-    fmt.Fprint(w, score)
+    p.sessions.remove(session)
+    p.games.Remove(session.id)
+    p.hub.Close(session.id)
 }
 
-func (p *PlayerServer) processWin(w http.ResponseWriter, player string) {
-	p.store.RecordWin(player)
-    w.WriteHeader(http.StatusAccepted)
+// parseStart reads the players and blind structure to start a game with,
+// e.g. {"players": ["Ruth", "Chris", "Cleo"], "blindStructure": "turbo"}.
+func (p *PlayerServer) parseStart(msg Message) ([]string, BlindStructure, error) {
+    var start StartPayload
+
+    if err := msg.DecodePayload(&start); err != nil {
+        return nil, DefaultBlindStructure, err
+    }
+
+    return p.checkStart(start)
 }
 
-type playerServerWS struct {
-    *websocket.Conn
+// checkStart checks the roster and looks up the blind structure to start a
+// game with.
+func (p *PlayerServer) checkStart(start StartPayload) ([]string, BlindStructure, error) {
+    blinds := DefaultBlindStructure
+
+    if err := ValidateRoster(start.Players, DefaultMinPlayers, DefaultMaxPlayers); err != nil {
+        return nil, blinds, fmt.Errorf("bad roster, %w", err)
+    }
+
+    if start.BlindStructure != "" {
+        named, ok := p.blindStructures[start.BlindStructure]
+        if !ok {
+            return nil, blinds, fmt.Errorf("unknown blind structure %q", start.BlindStructure)
+        }
+        blinds = named
+    }
+
+    return start.Players, blinds, nil
 }
 
-func (w *playerServerWS) Write(p []byte) (n int, err error) {
-    err = w.WriteMessage(websocket.TextMessage, p)
+// finishGame declares the result sent in msg, reporting whether the game is
+// over. A result naming the wrong players can be sent again.
+func (p *PlayerServer) finishGame(ws *playerServerWS, session *gameSession, msg Message) bool {
+    var result FinishPayload
+
+    if err := msg.DecodePayload(&result); err != nil {
+        ws.SendError(err)
+        return false
+    }
+
+    err := p.finish(session, result)
 
     if err != nil {
-        return 0, err
+        ws.SendError(err)
+    }
+
+    return err == nil
+}
+
+// finish declares the result of session's game and tells its spectators. A
+// result naming the wrong players, or one that couldn't be recorded, leaves
+// the game running to be declared again, otherwise the game is over and the
+// session ends.
+func (p *PlayerServer) finish(session *gameSession, result FinishPayload) error {
+    if strings.TrimSpace(result.Winner) == "" {
+        return ErrNoWinner
     }
 
-    return len(p), nil
+    if err := session.game.Finish(result.Winner, result.Placings...); err != nil {
+        return err
+    }
+
+    p.hub.Broadcast(session.id, FinishMessage, result)
+    p.endSession(session)
+    return nil
+}
+
+func isBadResult(err error) bool {
+    return errors.Is(err, ErrNoWinner) || errors.Is(err, ErrUnknownPlayer) || errors.Is(err, ErrDuplicatePlayer)
 }
 
-func newPlayerServerWS(w http.ResponseWriter, r *http.Request) *playerServerWS {
-    conn, err := wsUpgrader.Upgrade(w, r, nil)
+// controlClock carries out a blind clock command sent over the WebSocket and
+// replies, and tells the game's spectators, where the clock is at.
+func (p *PlayerServer) controlClock(ws *playerServerWS, game Game, id string, cmd ClockCommand) {
+    state, err := game.ControlClock(cmd)
 
     if err != nil {
-        log.Printf("problem upgrading connection to WebSockets %v\n", err)
+        ws.SendError(err)
+        return
     }
 
-    return &playerServerWS{conn}
+    ws.SendState(id, state)
+    p.hub.Broadcast(id, StateMessage, newStatePayload(id, state))
 }
 
-func (w *playerServerWS) WaitForMsg() string {
-    _, msg, err := w.ReadMessage()
+func (p *PlayerServer) sendClockState(ws *playerServerWS, game Game, id string) {
+    state, err := game.ClockState()
+
     if err != nil {
-        log.Printf("error reading from websocket %v\n", err)
+        ws.SendError(err)
+        return
     }
-    return string(msg)
+
+    ws.SendState(id, state)
+}
+
+// watchHandler lets a spectator follow the running game with id: where the
+// clock is at when they join and whenever it changes, and the result. Games
+// are controlled over /ws, so anything a spectator sends is an error.
+func (p *PlayerServer) watchHandler(w http.ResponseWriter, r *http.Request, id string) {
+    // watch before looking the game up, a game is removed before its
+    // spectators are stopped so one found now will stop them when it ends
+    messages, stop := p.hub.Watch(id)
+    defer stop()
+
+    game, found := p.games.Get(id)
+
+    if !found {
+        writeError(w, http.StatusNotFound, fmt.Sprintf("no game %q running", id))
+        return
+    }
+
+    ws, err := newPlayerServerWS(w, r)
+    if err != nil {
+        return
+    }
+    defer ws.Close()
+
+    left := make(chan struct{})
+
+    go func() {
+        defer close(left)
+
+        for {
+            _, err := ws.Receive()
+            if err != nil && !errors.Is(err, ErrBadMessage) {
+                return
+            }
+            ws.SendError(ErrSpectator)
+        }
+    }()
+
+    p.sendClockState(ws, game, id)
+
+    for {
+        select {
+        case msg, ok := <-messages:
+            if !ok {
+                return
+            }
+
+            ws.SendMessage(msg)
+
+            // the blinds went up, so say how long the new level has left
+            if msg.Type == BlindMessage {
+                p.sendClockState(ws, game, id)
+            }
+        case <-left:
+            return
+        }
+    }
+}
+
+// Name matching modes for GET /players/{name}?match=, exact by default.
+const (
+    matchExact      = "exact"
+    matchNormalized = "normalized"
+)
+
+// showScore replies with the player's wins. With ?match=normalized a player
+// whose name is only written differently, e.g. "chris" for "Chris", is found
+// when there's nobody with exactly that name. See NormalizeName.
+func (p *PlayerServer) showScore(w http.ResponseWriter, r *http.Request, name string) {
+    match := r.URL.Query().Get("match")
+
+    if match != "" && match != matchExact && match != matchNormalized {
+        writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown match %q, want %s or %s", match, matchExact, matchNormalized))
+        return
+    }
+
+    player, found := p.store.GetPlayer(name)
+
+    if !found && match == matchNormalized {
+        if normalized := p.store.GetLeague(WholeLeague).Players().FindNormalized(name); normalized != nil {
+            player, found = *normalized, true
+        }
+    }
+
+    if !found {
+        writeError(w, http.StatusNotFound, fmt.Sprintf("no player named %q", name))
+        return
+    }
+
+    fmt.Fprint(w, player.Wins)
+}
+
+// processWin records a win for player with no game played, see Player.
+func (p *PlayerServer) processWin(w http.ResponseWriter, player string) {
+	if err := p.store.RecordWin(player); err != nil {
+        log.Printf("problem recording win %v\n", err)
+        writeError(w, http.StatusInternalServerError, "problem recording the win")
+        return
+    }
+    w.WriteHeader(http.StatusAccepted)
 }
 
+// PlayerStore keeps the league. GetPlayer reports whether a player has ever
+// won or played a game, GetPlayerScore is 0 for players who haven't.
+//
+// GetLeague returns the part of the league a LeagueQuery asks for, e.g.
+// WholeLeague, ranked with ties on wins broken by its TieBreak. A league for a
+// window of time counts only the wins and games in it, leaving out anyone who
+// didn't win or play in it. GetLeagueIn is the whole league for a window,
+// ranked by wins.
+//
+// RecordResult records a finished game and a win for its winner together, so
+// that either both are recorded or neither is. Recording a game rates it, and
+// GetRatings returns everyone's ratings as of the last game, the same as
+// RateGames replaying every game.
+//
+// Players can be renamed, merged into another player or deleted, which fail
+// with ErrPlayerNotFound if there's no such player. Renaming to the name of
+// another player fails with ErrPlayerExists, merge them instead.
 type PlayerStore interface {
+    GetPlayer(name string) (Player, bool)
     GetPlayerScore(name string) int
-	RecordWin(name string)
-    GetLeague() League
+	RecordWin(name string) error
+    GetLeague(query LeagueQuery) Standings
+    GetLeagueIn(window Window) League
+    RecordGame(game GameRecord) (GameRecord, error)
+    RecordResult(game GameRecord) (GameRecord, error)
+    GetGames() []GameRecord
+    GetRatings() Ratings
+    GetGame(id string) (GameRecord, bool)
+    RenamePlayer(from, to string) error
+    MergePlayers(from, into string) error
+    DeletePlayer(name string) error
 }
 
 func GetPlayerScore(player string) string {
@@ -146,7 +929,11 @@ func GetPlayerScore(player string) string {
 	return ""
 }
 
+// Player is a player's standing in the league. Played counts the recorded
+// games they took part in, while Wins also counts wins posted on their own to
+// /players/{name} with no game behind them, so Wins can be more than Played.
 type Player struct {
-    Name string
-    Wins int
+    Name   string
+    Wins   int
+    Played int
 }
\ No newline at end of file
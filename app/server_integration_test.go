@@ -3,6 +3,7 @@ package poker
 import (
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 )
 
@@ -14,7 +15,7 @@ func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 
     assertNoError(t, err)
 
-	server, _ := NewPlayerServer(store, DummyGame)
+	server, _ := NewPlayerServer(store, SameGame(DummyGame))
 	player := "Pepper"
 
 	server.ServeHTTP(httptest.NewRecorder(), newPostWinRequest(player))
@@ -39,8 +40,85 @@ func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 
         got := getLeagueFromResponse(t, response.Body)
         want := []Player{
-            {"Pepper", 3},
+            {"Pepper", 3, 0},
         }
         assertLeague(t, got, want)
     })
-}
\ No newline at end of file
+}
+
+func TestPlayingAGameOverHTTP(t *testing.T) {
+	store := NewInMemoryPlayerStore()
+	newGame := func() Game { return NewTexasHoldem(&SpyBlindAlerter{}, store) }
+
+	server, err := NewPlayerServer(store, newGame)
+	assertNoError(t, err)
+
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, newPostGameRequest(`{"players": ["Ruth", "Chris", "Cleo"]}`))
+	assertStatus(t, response, http.StatusCreated)
+
+	var started ActiveGame
+	decodeJSON(t, response.Body, &started)
+
+	response = httptest.NewRecorder()
+	server.ServeHTTP(response, newPostFinishRequest(started.ID, `{"winner": "Cleo", "placings": ["Ruth", "Chris"]}`))
+	assertStatus(t, response, http.StatusOK)
+
+	var finished GameRecord
+	decodeJSON(t, response.Body, &finished)
+
+	if finished.ID != started.ID || finished.Winner() != "Cleo" {
+		t.Errorf("got %+v, want game %s won by Cleo", finished, started.ID)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "/games/"+started.ID, nil)
+	response = httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+
+	var record GameRecord
+	decodeJSON(t, response.Body, &record)
+	assertGameRecord(t, record, finished)
+
+	assertScoreEquals(t, store.GetPlayerScore("Cleo"), 1)
+}
+
+func TestRecordingWinsConcurrently(t *testing.T) {
+	database, cleanDatabase := createTempFile(t, "[]")
+	defer cleanDatabase()
+
+	store, err := NewFileSystemPlayerStore(database)
+
+	assertNoError(t, err)
+
+	server, _ := NewPlayerServer(store, SameGame(DummyGame))
+	players := []string{"Pepper", "Floyd", "Cleo", "Chris"}
+	winsEach := 50
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < winsEach*len(players); i++ {
+		wg.Add(2)
+
+		go func(player string) {
+			defer wg.Done()
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, newPostWinRequest(player))
+			assertStatus(t, response, http.StatusAccepted)
+		}(players[i%len(players)])
+
+		go func() {
+			defer wg.Done()
+			server.ServeHTTP(httptest.NewRecorder(), newLeagueRequest())
+		}()
+	}
+
+	wg.Wait()
+
+	for _, player := range players {
+		assertScoreEquals(t, store.GetPlayerScore(player), winsEach)
+	}
+
+	for _, player := range reopenFileSystemStore(t, database).GetLeague(WholeLeague) {
+		assertScoreEquals(t, player.Wins, winsEach)
+	}
+}
@@ -2,12 +2,14 @@ package poker
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,15 +18,13 @@ import (
 
 
 func TestGETPlayers(t *testing.T) {
-	store := StubPlayerStore{
-        map[string]int{
-            "Pepper": 20,
-            "Floyd":  10,
-        },
-		nil,
-		nil,
-    }
-	server, _ := NewPlayerServer(&store, DummyGame)
+	store := NewInMemoryPlayerStore(
+        Player{"Pepper", 20, 0},
+        Player{"Floyd", 10, 0},
+        Player{"Ruth Smith", 0, 3},
+        Player{"AC/DC", 2, 2},
+    )
+	server, _ := NewPlayerServer(store, SameGame(DummyGame))
 
     t.Run("returns Pepper's score", func(t *testing.T) {
         request := newGetScoreRequest("Pepper")
@@ -40,71 +40,795 @@ func TestGETPlayers(t *testing.T) {
         request := newGetScoreRequest("Floyd")
         response := httptest.NewRecorder()
 
-        server.ServeHTTP(response, request)
+        server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusOK)
+        assertResponseBody(t, response.Body.String(), "10")
+    })
+
+	t.Run("returns 0 for players who have played but never won", func(t *testing.T) {
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, newGetScoreRequest("Ruth%20Smith"))
+
+		assertStatus(t, response, http.StatusOK)
+		assertResponseBody(t, response.Body.String(), "0")
+	})
+
+	t.Run("decodes names with slashes in them", func(t *testing.T) {
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, newGetScoreRequest("AC%2FDC"))
+
+		assertStatus(t, response, http.StatusOK)
+		assertResponseBody(t, response.Body.String(), "2")
+	})
+
+	t.Run("finds players written differently with match=normalized", func(t *testing.T) {
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, newGetScoreRequest("ruth%20%20SMITH?match=normalized"))
+
+		assertStatus(t, response, http.StatusOK)
+		assertResponseBody(t, response.Body.String(), "0")
+	})
+
+	t.Run("only finds exact names by default", func(t *testing.T) {
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, newGetScoreRequest("pepper"))
+
+		assertStatus(t, response, http.StatusNotFound)
+	})
+
+	t.Run("rejects unknown ways of matching names", func(t *testing.T) {
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, newGetScoreRequest("Pepper?match=fuzzy"))
+
+		assertAPIError(t, response, http.StatusBadRequest, `unknown match "fuzzy", want exact or normalized`)
+	})
+
+	t.Run("returns 404 on missing players", func(t *testing.T) {
+		request := newGetScoreRequest("Apollo")
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusNotFound)
+		assertAPIError(t, response, http.StatusNotFound, `no player named "Apollo"`)
+	})
+}
+
+func TestRouting(t *testing.T) {
+	server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), DummyGame)
+
+	cases := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{http.MethodPut, "/players/Pepper", "DELETE, GET, POST"},
+		{http.MethodDelete, "/players/Pepper/merge", "POST"},
+		{http.MethodPost, "/league", "GET"},
+		{http.MethodDelete, "/games", "GET, POST"},
+		{http.MethodPost, "/games/active", "GET"},
+		{http.MethodPut, "/games/abc", "GET"},
+		{http.MethodGet, "/games/abc/finish", "POST"},
+		{http.MethodPost, "/game", "GET"},
+		{http.MethodPost, "/ws", "GET"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.method+" "+c.path+" is not allowed", func(t *testing.T) {
+			request, _ := http.NewRequest(c.method, c.path, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, http.StatusMethodNotAllowed)
+
+			if got := response.Header().Get("allow"); got != c.allow {
+				t.Errorf("got Allow %q, want %q", got, c.allow)
+			}
+
+			assertAPIError(t, response, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not allowed, use %s", c.method, c.allow))
+		})
+	}
+
+	for _, path := range []string{"/", "/nope", "/players/", "/players/Pepper/wins", "/games/abc/deal"} {
+		t.Run(path+" is not found", func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, path, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, http.StatusNotFound)
+			assertContentType(t, response, jsonContentType)
+		})
+	}
+}
+
+func TestStoreWins(t *testing.T) {
+    store := NewInMemoryPlayerStore()
+    server, _ := NewPlayerServer(store, SameGame(DummyGame))
+
+	t.Run("it records wins on POST", func(t *testing.T) {
+		player := "Pepper"
+	
+		request := newPostWinRequest(player)
+		response := httptest.NewRecorder()
+	
+		server.ServeHTTP(response, request)
+	
+		assertStatus(t, response, http.StatusAccepted)
+        assertScoreEquals(t, store.GetPlayerScore(player), 1)
+	})
+
+	t.Run("it records wins for URL-encoded names", func(t *testing.T) {
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, newPostWinRequest("Ruth%20Smith"))
+
+		assertStatus(t, response, http.StatusAccepted)
+		assertScoreEquals(t, store.GetPlayerScore("Ruth Smith"), 1)
+	})
+
+	t.Run("it returns 500 when the win can't be recorded", func(t *testing.T) {
+		store := &StubPlayerStore{winErr: errors.New("disk full")}
+		server := mustMakePlayerServer(t, store, DummyGame)
+
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newPostWinRequest("Pepper"))
+
+		assertStatus(t, response, http.StatusInternalServerError)
+		assertAPIError(t, response, http.StatusInternalServerError, "problem recording the win")
+	})
+}
+
+func TestManagePlayers(t *testing.T) {
+    newServer := func(t *testing.T) (*PlayerServer, *InMemoryPlayerStore) {
+        store := NewInMemoryPlayerStore(Player{"Chris", 3, 4}, Player{"chris", 1, 2}, Player{"Cleo", 2, 2})
+        return mustMakePlayerServer(t, store, DummyGame), store
+    }
+
+    t.Run("renames a player", func(t *testing.T) {
+        server, store := newServer(t)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newPlayerAdminRequest(http.MethodPost, "Cleo/rename", `{"name": "Cleo Smith"}`))
+
+        assertStatus(t, response, http.StatusOK)
+
+        var got Player
+        decodeJSON(t, response.Body, &got)
+        if want := (Player{"Cleo Smith", 2, 2}); got != want {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+
+        if _, found := store.GetPlayer("Cleo"); found {
+            t.Error("didn't expect to find the player by their old name")
+        }
+    })
+
+    t.Run("merges a player into another", func(t *testing.T) {
+        server, store := newServer(t)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newPlayerAdminRequest(http.MethodPost, "chris/merge", `{"into": "Chris"}`))
+
+        assertStatus(t, response, http.StatusOK)
+
+        var got Player
+        decodeJSON(t, response.Body, &got)
+        if want := (Player{"Chris", 4, 6}); got != want {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+
+        assertLeague(t, store.GetLeague(WholeLeague).Players(), []Player{{"Chris", 4, 6}, {"Cleo", 2, 2}})
+    })
+
+    t.Run("deletes a player", func(t *testing.T) {
+        server, store := newServer(t)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newPlayerAdminRequest(http.MethodDelete, "chris", ""))
+
+        assertStatus(t, response, http.StatusNoContent)
+        assertLeague(t, store.GetLeague(WholeLeague).Players(), []Player{{"Chris", 3, 4}, {"Cleo", 2, 2}})
+    })
+
+    cases := []struct {
+        name    string
+        method  string
+        path    string
+        body    string
+        status  int
+        message string
+    }{
+        {"renaming a missing player", http.MethodPost, "Apollo/rename", `{"name": "Floyd"}`, http.StatusNotFound, `no player named "Apollo"`},
+        {"renaming to a taken name", http.MethodPost, "chris/rename", `{"name": "Chris"}`, http.StatusConflict, `there is already a player named "Chris"`},
+        {"renaming to nothing", http.MethodPost, "chris/rename", `{"name": ""}`, http.StatusBadRequest, "a player has no name"},
+        {"renaming with a bad body", http.MethodPost, "chris/rename", `{`, http.StatusBadRequest, "problem parsing rename, unexpected EOF"},
+        {"merging a missing player", http.MethodPost, "Apollo/merge", `{"into": "Chris"}`, http.StatusNotFound, `no player named "Apollo"`},
+        {"merging a player into themselves", http.MethodPost, "Chris/merge", `{"into": "Chris"}`, http.StatusBadRequest, `can't merge a player into themselves, "Chris"`},
+        {"deleting a missing player", http.MethodDelete, "Apollo", "", http.StatusNotFound, `no player named "Apollo"`},
+        {"getting a rename", http.MethodGet, "Chris/rename", "", http.StatusMethodNotAllowed, "GET is not allowed, use POST"},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            server, store := newServer(t)
+            response := httptest.NewRecorder()
+
+            server.ServeHTTP(response, newPlayerAdminRequest(c.method, c.path, c.body))
+
+            assertAPIError(t, response, c.status, c.message)
+            assertLeague(t, store.GetLeague(WholeLeague).Players(), []Player{{"Chris", 3, 4}, {"Cleo", 2, 2}, {"chris", 1, 2}})
+        })
+    }
+}
+
+func TestLeague(t *testing.T) {
+	t.Run("it returns the league table as JSON", func(t *testing.T) {
+        wantedLeague := []Player{
+            {"Cleo", 32, 0},
+            {"Chris", 20, 0},
+            {"Tiest", 14, 0},
+        }
+
+        store := NewInMemoryPlayerStore(wantedLeague...)
+        server, _ := NewPlayerServer(store, SameGame(DummyGame))
+
+        request := newLeagueRequest()
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        got := getLeagueFromResponse(t, response.Body)
+        assertStatus(t, response, http.StatusOK)
+		assertLeague(t, got, wantedLeague)
+		assertContentType(t, response, jsonContentType)
+    })
+
+    t.Run("it returns the league by rating with ?sort=rating", func(t *testing.T) {
+        store := NewInMemoryPlayerStore(Player{"Cleo", 32, 0}, Player{"Chris", 20, 0})
+        store.RecordGame(GameRecord{Participants: []string{"Cleo", "Chris"}, FinishingOrder: []string{"Chris", "Cleo"}})
+        server := mustMakePlayerServer(t, store, DummyGame)
+
+        request, _ := http.NewRequest(http.MethodGet, "/league?sort=rating", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        var got []RatedPlayer
+        decodeJSON(t, response.Body, &got)
+
+        want := []RatedPlayer{{Player{"Chris", 20, 1}, 1516, 1}, {Player{"Cleo", 32, 1}, 1484, 2}}
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+
+    t.Run("it rejects unknown sorts", func(t *testing.T) {
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), DummyGame)
+
+        request, _ := http.NewRequest(http.MethodGet, "/league?sort=luck", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        assertAPIError(t, response, http.StatusBadRequest, `unknown sort "luck", want wins, name or rating`)
+    })
+
+    t.Run("it ranks the league, sharing places between players level on wins", func(t *testing.T) {
+        store := NewInMemoryPlayerStore(Player{"Ruth", 2, 0}, Player{"Cleo", 3, 0}, Player{"Chris", 2, 0}, Player{"Tiest", 1, 0})
+        server := mustMakePlayerServer(t, store, DummyGame)
+        server.BreakTiesBy(TieBreakName)
+
+        request := newLeagueRequest()
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        var got []Standing
+        decodeJSON(t, response.Body, &got)
+
+        want := []Standing{{Player{"Cleo", 3, 0}, 1}, {Player{"Chris", 2, 0}, 2}, {Player{"Ruth", 2, 0}, 2}, {Player{"Tiest", 1, 0}, 4}}
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+
+    t.Run("it pages through the league with a Link header", func(t *testing.T) {
+        store := NewInMemoryPlayerStore(Player{"Cleo", 4, 0}, Player{"Chris", 3, 0}, Player{"Ruth", 2, 0}, Player{"Tiest", 1, 0}, Player{"Floyd", 0, 0})
+        server := mustMakePlayerServer(t, store, DummyGame)
+
+        cases := []struct {
+            path string
+            want []Player
+            link string
+        }{
+            {
+                "/league?limit=2",
+                []Player{{"Cleo", 4, 0}, {"Chris", 3, 0}},
+                `</league?limit=2&offset=0>; rel="first", </league?limit=2&offset=2>; rel="next"`,
+            },
+            {
+                "/league?limit=2&offset=3",
+                []Player{{"Tiest", 1, 0}, {"Floyd", 0, 0}},
+                `</league?limit=2&offset=0>; rel="first", </league?limit=2&offset=1>; rel="prev"`,
+            },
+            {
+                "/league?sort=name&prefix=C&limit=1",
+                []Player{{"Chris", 3, 0}},
+                `</league?limit=1&offset=0&prefix=C&sort=name>; rel="first", </league?limit=1&offset=1&prefix=C&sort=name>; rel="next"`,
+            },
+            {
+                "/league?offset=4",
+                []Player{{"Floyd", 0, 0}},
+                "",
+            },
+        }
+
+        for _, c := range cases {
+            request, _ := http.NewRequest(http.MethodGet, c.path, nil)
+            response := httptest.NewRecorder()
+
+            server.ServeHTTP(response, request)
+
+            assertStatus(t, response, http.StatusOK)
+            assertLeague(t, getLeagueFromResponse(t, response.Body), c.want)
+
+            if got := response.Header().Get("Link"); got != c.link {
+                t.Errorf("got Link %q for %s, want %q", got, c.path, c.link)
+            }
+        }
+    })
+
+    t.Run("it pages through the league by rating", func(t *testing.T) {
+        store := NewInMemoryPlayerStore(Player{"Cleo", 2, 0}, Player{"Chris", 1, 0}, Player{"Ruth", 0, 0})
+        store.RecordGame(GameRecord{Participants: []string{"Cleo", "Chris"}, FinishingOrder: []string{"Chris", "Cleo"}})
+        server := mustMakePlayerServer(t, store, DummyGame)
+
+        request, _ := http.NewRequest(http.MethodGet, "/league?sort=rating&offset=1&limit=1", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        var got []RatedPlayer
+        decodeJSON(t, response.Body, &got)
+
+        if want := []RatedPlayer{{Player{"Ruth", 0, 0}, InitialRating, 2}}; !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+
+        if link := response.Header().Get("Link"); !strings.Contains(link, `rel="next"`) {
+            t.Errorf("got Link %q, want a next page", link)
+        }
+    })
+
+    t.Run("it rejects bad pages", func(t *testing.T) {
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), DummyGame)
+
+        bad := map[string]string{
+            "/league?limit=0":   `limit "0" isn't a number of players greater than 0`,
+            "/league?limit=ten": `limit "ten" isn't a number of players greater than 0`,
+            "/league?offset=-1":  `offset "-1" isn't a number of players to skip`,
+        }
+
+        for path, message := range bad {
+            request, _ := http.NewRequest(http.MethodGet, path, nil)
+            response := httptest.NewRecorder()
+
+            server.ServeHTTP(response, request)
+
+            assertAPIError(t, response, http.StatusBadRequest, message)
+        }
+    })
+}
+
+func TestSeasonLeague(t *testing.T) {
+    store := NewInMemoryPlayerStore()
+
+    winAt := func(name string, at time.Time) {
+        store.now = func() time.Time { return at }
+        store.RecordWin(name)
+    }
+
+    winAt("Cleo", time.Date(2026, time.June, 30, 23, 0, 0, 0, time.UTC))
+    winAt("Chris", time.Date(2026, time.July, 1, 20, 0, 0, 0, time.UTC))
+    winAt("Chris", time.Date(2026, time.August, 1, 20, 0, 0, 0, time.UTC))
+    winAt("Cleo", time.Date(2026, time.August, 1, 21, 0, 0, 0, time.UTC))
+    store.RecordGame(GameRecord{
+        FinishedAt:     time.Date(2026, time.August, 1, 21, 0, 0, 0, time.UTC),
+        Participants:   []string{"Cleo", "Ruth"},
+        FinishingOrder: []string{"Cleo", "Ruth"},
+    })
+
+    server := mustMakePlayerServer(t, store, DummyGame)
+    server.RegisterSeason(Season{"summer", time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC)})
+
+    cases := map[string][]Player{
+        "/league":                               {{"Cleo", 2, 1}, {"Chris", 2, 0}, {"Ruth", 0, 1}},
+        "/league?season=2026-Q3":                {{"Chris", 2, 0}, {"Cleo", 1, 1}, {"Ruth", 0, 1}},
+        "/league?season=2026-Q2":                {{"Cleo", 1, 0}},
+        "/league?season=summer":                 {{"Cleo", 1, 0}},
+        "/league?from=2026-08-01":               {{"Cleo", 1, 1}, {"Chris", 1, 0}, {"Ruth", 0, 1}},
+        "/league?from=2026-07-01&to=2026-08-01": {{"Chris", 1, 0}},
+        "/league?to=2026-07-01T00:00:00Z":       {{"Cleo", 1, 0}},
+        "/league?season=2025-Q1":                {},
+    }
+
+    for path, want := range cases {
+        t.Run(path, func(t *testing.T) {
+            request, _ := http.NewRequest(http.MethodGet, path, nil)
+            response := httptest.NewRecorder()
+
+            server.ServeHTTP(response, request)
+
+            assertStatus(t, response, http.StatusOK)
+            assertLeague(t, getLeagueFromResponse(t, response.Body), want)
+        })
+    }
+
+    t.Run("rates only the games in the season", func(t *testing.T) {
+        request, _ := http.NewRequest(http.MethodGet, "/league?season=2026-Q2&sort=rating", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        var got []RatedPlayer
+        decodeJSON(t, response.Body, &got)
+
+        if want := []RatedPlayer{{Player{"Cleo", 1, 0}, InitialRating, 1}}; !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+
+    bad := map[string]string{
+        "/league?season=spring":                  `unknown season "spring", want a season like 2026-Q3`,
+        "/league?season=2026-Q3&from=2026-07-01": "bad time window, ask for a season or for from and to, not both",
+        "/league?from=soon":                      `bad time window, from "soon" is not a date or RFC 3339 time`,
+        "/league?from=2026-08-01&to=2026-07-01":  "bad time window, from 2026-08-01 isn't before to 2026-07-01",
+    }
+
+    for path, message := range bad {
+        t.Run(path, func(t *testing.T) {
+            request, _ := http.NewRequest(http.MethodGet, path, nil)
+            response := httptest.NewRecorder()
+
+            server.ServeHTTP(response, request)
+
+            assertAPIError(t, response, http.StatusBadRequest, message)
+        })
+    }
+}
+
+func TestStatsAPI(t *testing.T) {
+    finishedAt := time.Date(2026, time.July, 1, 22, 0, 0, 0, time.UTC)
+    store := NewInMemoryPlayerStore(Player{"Floyd", 1, 0})
+    store.RecordGame(GameRecord{FinishedAt: finishedAt, Participants: []string{"Cleo", "Chris"}, FinishingOrder: []string{"Chris", "Cleo"}})
+    server := mustMakePlayerServer(t, store, DummyGame)
+
+    t.Run("returns a player's stats as JSON", func(t *testing.T) {
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newGetScoreRequest("Chris/stats"))
+
+        assertStatus(t, response, http.StatusOK)
+        assertContentType(t, response, jsonContentType)
+
+        var got PlayerStats
+        decodeJSON(t, response.Body, &got)
+
+        want := PlayerStats{
+            Name:             "Chris",
+            GameWins:         1,
+            Played:           1,
+            WinRate:          1,
+            CurrentStreak:    1,
+            LongestStreak:    1,
+            AverageFieldSize: 2,
+            LastPlayed:       &finishedAt,
+            HeadToHead:       []HeadToHead{{Opponent: "Cleo", Played: 1, Wins: 1}},
+        }
+
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+
+    t.Run("players who haven't played have never played", func(t *testing.T) {
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newGetScoreRequest("Floyd/stats"))
+
+        var got map[string]interface{}
+        decodeJSON(t, response.Body, &got)
+
+        if got["LastPlayed"] != nil || got["Played"] != 0.0 {
+            t.Errorf("got %v, want no games played", got)
+        }
+    })
+
+    t.Run("returns 404 for missing players", func(t *testing.T) {
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newGetScoreRequest("Apollo/stats"))
+
+        assertAPIError(t, response, http.StatusNotFound, `no player named "Apollo"`)
+    })
+}
+
+func TestRatingsAPI(t *testing.T) {
+    store := NewInMemoryPlayerStore(Player{"Floyd", 0, 0})
+    store.RecordGame(GameRecord{ID: "abc", Participants: []string{"Cleo", "Chris"}, FinishingOrder: []string{"Chris", "Cleo"}})
+    server := mustMakePlayerServer(t, store, DummyGame)
+
+    t.Run("returns a player's rating and its history", func(t *testing.T) {
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newGetScoreRequest("Chris/rating"))
+
+        assertStatus(t, response, http.StatusOK)
+        assertContentType(t, response, jsonContentType)
+
+        var got PlayerRating
+        decodeJSON(t, response.Body, &got)
+
+        want := PlayerRating{"Chris", 1516, []RatingChange{{GameID: "abc", Place: 1, Before: 1500, After: 1516}}}
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+
+    t.Run("players who haven't played have the initial rating", func(t *testing.T) {
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newGetScoreRequest("Floyd/rating"))
+
+        var got PlayerRating
+        decodeJSON(t, response.Body, &got)
+
+        if got.Rating != InitialRating || len(got.History) != 0 {
+            t.Errorf("got %+v, want the initial rating and no history", got)
+        }
+    })
+
+    t.Run("returns 404 for missing players", func(t *testing.T) {
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, newGetScoreRequest("Apollo/rating"))
+
+        assertAPIError(t, response, http.StatusNotFound, `no player named "Apollo"`)
+    })
+}
+
+func TestGames(t *testing.T) {
+    played := GameRecord{
+        ID:              "abc123",
+        StartedAt:       time.Date(2021, 2, 18, 20, 0, 0, 0, time.UTC),
+        FinishedAt:      time.Date(2021, 2, 18, 22, 0, 0, 0, time.UTC),
+        NumberOfPlayers: 3,
+        Participants:    []string{"Cleo", "Chris"},
+        FinishingOrder:  []string{"Cleo", "Chris"},
+        BlindStructure:  "default",
+    }
+
+    store := NewInMemoryPlayerStore()
+    store.RecordGame(played)
+    server := mustMakePlayerServer(t, store, DummyGame)
+
+    t.Run("GET /games returns every game as JSON", func(t *testing.T) {
+        request, _ := http.NewRequest(http.MethodGet, "/games", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        var got []GameRecord
+        decodeJSON(t, response.Body, &got)
+
+        assertStatus(t, response, http.StatusOK)
+        assertContentType(t, response, jsonContentType)
+
+        if !reflect.DeepEqual(got, []GameRecord{played}) {
+            t.Errorf("got %+v want %+v", got, []GameRecord{played})
+        }
+    })
+
+    t.Run("GET /games/{id} returns the game", func(t *testing.T) {
+        request, _ := http.NewRequest(http.MethodGet, "/games/abc123", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        var got GameRecord
+        decodeJSON(t, response.Body, &got)
+
+        assertStatus(t, response, http.StatusOK)
+        if !reflect.DeepEqual(got, played) {
+            t.Errorf("got %+v want %+v", got, played)
+        }
+    })
+
+    t.Run("GET /games/{id} returns 404 for unknown games", func(t *testing.T) {
+        request, _ := http.NewRequest(http.MethodGet, "/games/nope", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        assertStatus(t, response, http.StatusNotFound)
+    })
+}
+
+func TestGamesAPI(t *testing.T) {
+    t.Run("POST /games starts a game at a new table", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc", Clock: ClockState{Level: 1, SmallBlind: 100, BigBlind: 200}}
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), game)
+
+        response := httptest.NewRecorder()
+        server.ServeHTTP(response, newPostGameRequest(`{"players": ["Ruth", "Chris", "Cleo"], "blindStructure": "turbo"}`))
+
+        assertStatus(t, response, http.StatusCreated)
+        assertContentType(t, response, jsonContentType)
+
+        if got := response.Header().Get("location"); got != "/games/abc" {
+            t.Errorf("got location %q, want /games/abc", got)
+        }
+
+        var got ActiveGame
+        decodeJSON(t, response.Body, &got)
+
+        if got.ID != "abc" || got.BlindStructure != "turbo" || got.Clock != game.Clock {
+            t.Errorf("got %+v, want game abc with its turbo clock", got)
+        }
+
+        assertGameStartedWith(t, game, 3)
+    })
+
+    t.Run("POST /games rejects bad games", func(t *testing.T) {
+        cases := map[string]string{
+            "not JSON":                   `3 players`,
+            "a bad roster":               `{"players": ["Ruth"]}`,
+            "an unknown blind structure": `{"players": ["Ruth", "Chris"], "blindStructure": "hyper"}`,
+        }
+
+        for name, body := range cases {
+            t.Run(name, func(t *testing.T) {
+                game := &GameSpy{}
+                server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), game)
+
+                response := httptest.NewRecorder()
+                server.ServeHTTP(response, newPostGameRequest(body))
+
+                assertStatus(t, response, http.StatusBadRequest)
+
+                if game.StartCalled {
+                    t.Error("game should not have started")
+                }
+            })
+        }
+    })
+
+    t.Run("GET /games/{id} returns where a running game's clock is at", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc", Clock: ClockState{Level: 3, SmallBlind: 300, BigBlind: 600, Remaining: time.Minute}}
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), game)
+        server.ServeHTTP(httptest.NewRecorder(), newPostGameRequest(`{"players": ["Ruth", "Chris"]}`))
+
+        request, _ := http.NewRequest(http.MethodGet, "/games/abc", nil)
+        response := httptest.NewRecorder()
+        server.ServeHTTP(response, request)
+
+        assertStatus(t, response, http.StatusOK)
+
+        var got ActiveGame
+        decodeJSON(t, response.Body, &got)
+
+        if got.Clock != game.Clock || !reflect.DeepEqual(got.Players, []string{"Ruth", "Chris"}) {
+            t.Errorf("got %+v, want Ruth and Chris's game with its clock", got)
+        }
+    })
+
+    t.Run("GET /games/{id} describes the clock as the WebSocket does", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc", Clock: ClockState{Level: 2, SmallBlind: 200, BigBlind: 400, Ante: 25, Remaining: 7 * time.Minute}}
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), game)
+        server.ServeHTTP(httptest.NewRecorder(), newPostGameRequest(`{"players": ["Ruth", "Chris"]}`))
+
+        request, _ := http.NewRequest(http.MethodGet, "/games/abc", nil)
+        response := httptest.NewRecorder()
+        server.ServeHTTP(response, request)
+
+        var got struct{ Clock map[string]interface{} }
+        decodeJSON(t, response.Body, &got)
+
+        want := map[string]interface{}{"level": 2.0, "smallBlind": 200.0, "bigBlind": 400.0, "ante": 25.0, "remainingSeconds": 420.0, "paused": false}
+
+        if !reflect.DeepEqual(got.Clock, want) {
+            t.Errorf("got clock %v, want %v", got.Clock, want)
+        }
+    })
+
+    t.Run("POST /games/{id}/finish declares the result", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc"}
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), game)
+        server.ServeHTTP(httptest.NewRecorder(), newPostGameRequest(`{"players": ["Ruth", "Chris", "Cleo"]}`))
+
+        response := httptest.NewRecorder()
+        server.ServeHTTP(response, newPostFinishRequest("abc", `{"winner": "Ruth", "placings": ["Cleo", "Chris"]}`))
+
+        assertStatus(t, response, http.StatusNoContent)
+        assertFinishCalledWith(t, game, "Ruth")
+
+        if !reflect.DeepEqual(game.FinishedPlacings, []string{"Cleo", "Chris"}) {
+            t.Errorf("got placings %v, want Cleo then Chris", game.FinishedPlacings)
+        }
+
+        response = httptest.NewRecorder()
+        server.ServeHTTP(response, newPostFinishRequest("abc", `{"winner": "Ruth"}`))
+
+        assertStatus(t, response, http.StatusNotFound)
+    })
+
+    t.Run("POST /games/{id}/finish rejects results naming the wrong players", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc", FinishErr: fmt.Errorf("%w %q", ErrUnknownPlayer, "Pies")}
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), game)
+        server.ServeHTTP(httptest.NewRecorder(), newPostGameRequest(`{"players": ["Ruth", "Chris"]}`))
 
-		assertStatus(t, response, http.StatusOK)
-        assertResponseBody(t, response.Body.String(), "10")
+        for _, body := range []string{`{"winner": "Pies"}`, `{"placings": ["Ruth"]}`, `Ruth wins`} {
+            response := httptest.NewRecorder()
+            server.ServeHTTP(response, newPostFinishRequest("abc", body))
+
+            assertStatus(t, response, http.StatusBadRequest)
+        }
+
+        if _, found := server.games.Describe("abc"); !found {
+            t.Error("expected the game to still be running")
+        }
     })
 
-	t.Run("returns 404 on missing players", func(t *testing.T) {
-		request := newGetScoreRequest("Apollo")
-		response := httptest.NewRecorder()
+    t.Run("games started with POST /games that are never finished are abandoned", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc"}
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), game)
+        server.gameExpiry = 10 * time.Millisecond
+        server.ServeHTTP(httptest.NewRecorder(), newPostGameRequest(`{"players": ["Ruth", "Chris"]}`))
 
-		server.ServeHTTP(response, request)
+        passed := retryUntil(500*time.Millisecond, func() bool {
+            _, running := server.games.Describe("abc")
+            return !running
+        })
 
-		assertStatus(t, response, http.StatusNotFound)
-	})
-}
+        if !passed {
+            t.Fatal("expected the game to be abandoned once it expired")
+        }
 
-func TestStoreWins(t *testing.T) {
-    store := StubPlayerStore{
-        map[string]int{},
-		nil,
-		nil,
-    }
-    server, _ := NewPlayerServer(&store, DummyGame)
+        game.Lock()
+        defer game.Unlock()
 
-	t.Run("it records wins on POST", func(t *testing.T) {
-		player := "Pepper"
-	
-		request := newPostWinRequest(player)
-		response := httptest.NewRecorder()
-	
-		server.ServeHTTP(response, request)
-	
-		assertStatus(t, response, http.StatusAccepted)
-        AssertPlayerWin(t, &store, player)
-	})
-}
+        if game.StartedCtx.Err() == nil {
+            t.Error("expected the game's alerts to be cancelled")
+        }
 
-func TestLeague(t *testing.T) {
-	t.Run("it returns the league table as JSON", func(t *testing.T) {
-        wantedLeague := []Player{
-            {"Cleo", 32},
-            {"Chris", 20},
-            {"Tiest", 14},
+        if game.FinishedCalled {
+            t.Error("game should not have finished")
         }
+    })
 
-        store := StubPlayerStore{nil, nil, wantedLeague}
-        server, _ := NewPlayerServer(&store, DummyGame)
+    t.Run("POST /games/{id}/finish returns 404 for games that aren't running", func(t *testing.T) {
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), &GameSpy{})
 
-        request := newLeagueRequest()
         response := httptest.NewRecorder()
+        server.ServeHTTP(response, newPostFinishRequest("nope", `{"winner": "Ruth"}`))
 
-        server.ServeHTTP(response, request)
-
-        got := getLeagueFromResponse(t, response.Body)
-        assertStatus(t, response, http.StatusOK)
-		assertLeague(t, got, wantedLeague)
-		assertContentType(t, response, jsonContentType)
+        assertStatus(t, response, http.StatusNotFound)
     })
 }
 
 func TestGame(t *testing.T) {
-    var dummyPlayerStore = &StubPlayerStore{}
+    var dummyPlayerStore = NewInMemoryPlayerStore()
     
     t.Run("GET /game returns 200", func(t *testing.T) {
-        server := mustMakePlayerServer(t, &StubPlayerStore{}, DummyGame)
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), DummyGame)
 
         request := newGameRequest()
         response := httptest.NewRecorder()
@@ -115,26 +839,484 @@ func TestGame(t *testing.T) {
     })
 
     t.Run("start a game with 3 players, send some blind alerts down WS and declare Ruth the winner", func(t *testing.T) {
-        wantedBlindAlert := "Blind is 100"
+        alert := BlindAlert{2, BlindLevel{SmallBlind: 100, BigBlind: 200, Ante: 25, Duration: 10 * time.Minute}}
+        wantedBlindAlert := BlindPayload{
+            Message:         "Level 2, blinds 100/200 ante 25",
+            Level:           2,
+            SmallBlind:      100,
+            BigBlind:        200,
+            Ante:            25,
+            DurationSeconds: 600,
+        }
         winner := "Ruth"
     
-        game := &GameSpy{BlindAlert: []byte(wantedBlindAlert)}
+        game := &GameSpy{BlindAlert: &alert}
         server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
         ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
     
         defer server.Close()
         defer ws.Close()
     
-        writeWSMessage(t, ws, "3")
-        writeWSMessage(t, ws, winner)
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris", "Cleo"}})
+        writeWSMessage(t, ws, FinishMessage, FinishPayload{Winner: winner, Placings: []string{"Cleo"}})
     
-        time.Sleep(10 * time.Millisecond)
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, ws, BlindMessage, wantedBlindAlert) })
         assertGameStartedWith(t, game, 3)
         assertFinishCalledWith(t, game, winner)
-        within(t, 10 * time.Millisecond, func() { assertWebsocketGotMsg(t, ws, wantedBlindAlert) })
+
+        game.Lock()
+        defer game.Unlock()
+
+        if !reflect.DeepEqual(game.FinishedPlacings, []string{"Cleo"}) {
+            t.Errorf("got placings %v, want Cleo", game.FinishedPlacings)
+        }
+    })
+
+    t.Run("replies with the clock's state when the game starts", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc", Clock: ClockState{Level: 1, SmallBlind: 100, BigBlind: 200, Remaining: 10 * time.Minute}}
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+        ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+
+        defer server.Close()
+        defer ws.Close()
+
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris", "Cleo"}})
+        readWSSession(t, ws)
+
+        want := StatePayload{GameID: "abc", Level: 1, SmallBlind: 100, BigBlind: 200, RemainingSeconds: 600}
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, ws, StateMessage, want) })
+    })
+
+    t.Run("pause and move the blind clock over WS", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc", Clock: ClockState{Level: 1, SmallBlind: 100, BigBlind: 200, Paused: true}}
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+        ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+
+        defer server.Close()
+        defer ws.Close()
+
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris", "Cleo"}})
+        ws.ReadMessage()
+
+        want := newStatePayload("abc", game.Clock)
+
+        writeWSMessage(t, ws, PauseMessage, PausePayload{Paused: true})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, ws, StateMessage, want) })
+
+        writeWSMessage(t, ws, ClockMessage, ClockPayload{Command: "advance"})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, ws, StateMessage, want) })
+
+        writeWSMessage(t, ws, PauseMessage, PausePayload{Paused: false})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, ws, StateMessage, want) })
+
+        writeWSMessage(t, ws, FinishMessage, FinishPayload{Winner: "Ruth"})
+        assertFinishCalledWith(t, game, "Ruth")
+
+        game.Lock()
+        defer game.Unlock()
+
+        wantCommands := []ClockCommand{PauseClock, AdvanceClock, ResumeClock}
+        if !reflect.DeepEqual(game.ClockCommands, wantCommands) {
+            t.Errorf("got clock commands %v, want %v", game.ClockCommands, wantCommands)
+        }
+    })
+
+    t.Run("start a game with a named blind structure", func(t *testing.T) {
+        game := &GameSpy{}
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+        ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+
+        defer server.Close()
+        defer ws.Close()
+
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris", "Cleo"}, BlindStructure: "turbo"})
+        writeWSMessage(t, ws, FinishMessage, FinishPayload{Winner: "Ruth"})
+
+        assertFinishCalledWith(t, game, "Ruth")
+        assertGameStartedWith(t, game, 3)
+
+        game.Lock()
+        defer game.Unlock()
+
+        if game.StartedWithBlinds.Name != TurboBlindStructure.Name {
+            t.Errorf("game did not start with %q blinds, got %q", TurboBlindStructure.Name, game.StartedWithBlinds.Name)
+        }
+    })
+
+    t.Run("replies with an error to bad start messages and waits for a good one", func(t *testing.T) {
+        game := &GameSpy{}
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+        ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+
+        defer server.Close()
+        defer ws.Close()
+
+        badMessages := []struct {
+            name string
+            send func()
+            want string
+        }{
+            {
+                "not JSON",
+                func() { ws.WriteMessage(websocket.TextMessage, []byte("3")) },
+                "bad message, json: cannot unmarshal number into Go value of type poker.Message",
+            },
+            {
+                "an unsupported version",
+                func() { ws.WriteJSON(Message{Version: 2, Type: StartMessage}) },
+                "bad message, unsupported version 2, want 1",
+            },
+            {
+                "an unknown type",
+                func() { ws.WriteJSON(Message{Version: ProtocolVersion, Type: "deal"}) },
+                `bad message, unknown type "deal"`,
+            },
+            {
+                "no payload",
+                func() { writeWSMessage(t, ws, StartMessage, nil) },
+                "bad message, start message has no payload",
+            },
+            {
+                "finishing before starting",
+                func() { writeWSMessage(t, ws, FinishMessage, FinishPayload{Winner: "Ruth"}) },
+                "unexpected message, finish before the game has started",
+            },
+            {
+                "a bad roster",
+                func() { writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Ruth"}}) },
+                `bad roster, player named more than once "Ruth"`,
+            },
+            {
+                "an unknown blind structure",
+                func() {
+                    writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}, BlindStructure: "hyper"})
+                },
+                `unknown blind structure "hyper"`,
+            },
+        }
+
+        for _, bad := range badMessages {
+            bad.send()
+            within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, ws, ErrorMessage, ErrorPayload{bad.want}) })
+        }
+
+        game.Lock()
+        startCalled := game.StartCalled
+        game.Unlock()
+
+        if startCalled {
+            t.Fatal("game should not have started")
+        }
+
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}})
+        assertGameStartedWith(t, game, 2)
+    })
+
+    t.Run("replies with an error when the result names players who aren't playing", func(t *testing.T) {
+        game := &GameSpy{FinishErr: fmt.Errorf("%w %q", ErrUnknownPlayer, "Pies")}
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+        ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+
+        defer server.Close()
+        defer ws.Close()
+
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}})
+        readWSSession(t, ws)
+        ws.ReadMessage()
+
+        writeWSMessage(t, ws, FinishMessage, FinishPayload{})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, ws, ErrorMessage, ErrorPayload{ErrNoWinner.Error()}) })
+
+        writeWSMessage(t, ws, FinishMessage, FinishPayload{Winner: "Pies"})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, ws, ErrorMessage, ErrorPayload{`player not in this game "Pies"`}) })
+
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}})
+        within(t, 100*time.Millisecond, func() {
+            assertWebsocketGotMsg(t, ws, ErrorMessage, ErrorPayload{"unexpected message, start while a game is running"})
+        })
+    })
+
+    t.Run("spectators watch the clock and the result of a running game", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc", Clock: ClockState{Level: 1, SmallBlind: 100, BigBlind: 200, Remaining: 10 * time.Minute}}
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+        defer server.Close()
+
+        dealer := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        defer dealer.Close()
+
+        writeWSMessage(t, dealer, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}})
+        readWSSession(t, dealer)
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, dealer, StateMessage, newStatePayload("abc", game.Clock)) })
+
+        tv := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/games/abc/watch")
+        defer tv.Close()
+
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, tv, StateMessage, newStatePayload("abc", game.Clock)) })
+
+        writeWSMessage(t, tv, PauseMessage, PausePayload{Paused: true})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, tv, ErrorMessage, ErrorPayload{ErrSpectator.Error()}) })
+
+        writeWSMessage(t, dealer, PauseMessage, PausePayload{Paused: true})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, tv, StateMessage, newStatePayload("abc", game.Clock)) })
+
+        writeWSMessage(t, dealer, FinishMessage, FinishPayload{Winner: "Ruth", Placings: []string{"Chris"}})
+        within(t, 100*time.Millisecond, func() {
+            assertWebsocketGotMsg(t, tv, FinishMessage, FinishPayload{Winner: "Ruth", Placings: []string{"Chris"}})
+        })
+
+        within(t, 100*time.Millisecond, func() {
+            if _, _, err := tv.ReadMessage(); err == nil {
+                t.Error("expected the spectator's connection to close once the game finished")
+            }
+        })
+    })
+
+    t.Run("can't watch a game that isn't running", func(t *testing.T) {
+        server := mustMakePlayerServer(t, dummyPlayerStore, &GameSpy{GameID: "abc"})
+
+        request, _ := http.NewRequest(http.MethodGet, "/games/xyz/watch", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        assertStatus(t, response, http.StatusNotFound)
+    })
+
+    t.Run("watching a game that has finished leaves nothing watching it", func(t *testing.T) {
+        server := mustMakePlayerServer(t, NewInMemoryPlayerStore(), &GameSpy{GameID: "abc"})
+        server.ServeHTTP(httptest.NewRecorder(), newPostGameRequest(`{"players": ["Ruth", "Chris"]}`))
+        server.ServeHTTP(httptest.NewRecorder(), newPostFinishRequest("abc", `{"winner": "Ruth"}`))
+
+        request, _ := http.NewRequest(http.MethodGet, "/games/abc/watch", nil)
+        response := httptest.NewRecorder()
+
+        server.ServeHTTP(response, request)
+
+        assertStatus(t, response, http.StatusNotFound)
+
+        server.hub.mu.Lock()
+        defer server.hub.mu.Unlock()
+
+        if len(server.hub.watchers) != 0 {
+            t.Errorf("got spectators left watching %v, want none", server.hub.watchers)
+        }
+    })
+
+    t.Run("plays a separate game at each table and lists them while they run", func(t *testing.T) {
+        tables := map[string]*GameSpy{"one": {GameID: "one"}, "two": {GameID: "two"}}
+        order := []string{"one", "two"}
+        var mu sync.Mutex
+
+        newGame := func() Game {
+            mu.Lock()
+            defer mu.Unlock()
+            game := tables[order[0]]
+            order = order[1:]
+            return game
+        }
+
+        playerServer, err := NewPlayerServer(dummyPlayerStore, newGame)
+        assertNoError(t, err)
+        server := httptest.NewServer(playerServer)
+        defer server.Close()
+
+        first := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        defer first.Close()
+        writeWSMessage(t, first, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}})
+        first.ReadMessage()
+
+        second := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        defer second.Close()
+        writeWSMessage(t, second, StartMessage, StartPayload{Players: []string{"Cleo", "Tiest"}})
+        second.ReadMessage()
+
+        assertActiveGames(t, playerServer, "one", "two")
+
+        writeWSMessage(t, second, FinishMessage, FinishPayload{Winner: "Cleo"})
+        assertFinishCalledWith(t, tables["two"], "Cleo")
+
+        tables["one"].Lock()
+        if tables["one"].FinishedCalled {
+            t.Error("finishing the second table's game should not finish the first's")
+        }
+        tables["one"].Unlock()
+
+        passed := retryUntil(500*time.Millisecond, func() bool {
+            return len(getActiveGames(t, playerServer)) == 1
+        })
+        if !passed {
+            t.Error("expected the finished game to stop being active")
+        }
+
+        assertActiveGames(t, playerServer, "one")
+    })
+
+    t.Run("resumes a game when the dealer reconnects with its token", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc", Clock: ClockState{Level: 2, SmallBlind: 200, BigBlind: 400, Remaining: 5 * time.Minute}}
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+        defer server.Close()
+
+        first := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        writeWSMessage(t, first, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}})
+        session := readWSSession(t, first)
+        first.ReadMessage()
+        first.Close()
+
+        second := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        defer second.Close()
+
+        writeWSMessage(t, second, ResumeMessage, ResumePayload{Token: "nope"})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, second, ErrorMessage, ErrorPayload{ErrUnknownSession.Error()}) })
+
+        writeWSMessage(t, second, ResumeMessage, ResumePayload{Token: session.Token})
+
+        if resumed := readWSSession(t, second); resumed != session {
+            t.Errorf("got session %+v, want %+v", resumed, session)
+        }
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, second, StateMessage, newStatePayload("abc", game.Clock)) })
+
+        game.Lock()
+        if game.StartedCtx.Err() != nil {
+            t.Errorf("expected the game to keep running while the dealer reconnected, %v", game.StartedCtx.Err())
+        }
+        game.Unlock()
+
+        writeWSMessage(t, second, FinishMessage, FinishPayload{Winner: "Chris"})
+        assertFinishCalledWith(t, game, "Chris")
+    })
+
+    t.Run("the dealer's latest connection takes over the game", func(t *testing.T) {
+        game := &GameSpy{GameID: "abc"}
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+        defer server.Close()
+
+        first := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        defer first.Close()
+        writeWSMessage(t, first, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}})
+        session := readWSSession(t, first)
+        first.ReadMessage()
+
+        second := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        defer second.Close()
+        writeWSMessage(t, second, ResumeMessage, ResumePayload{Token: session.Token})
+        readWSSession(t, second)
+
+        within(t, 100*time.Millisecond, func() {
+            if _, _, err := first.ReadMessage(); err == nil {
+                t.Error("expected the first connection to be closed")
+            }
+        })
+    })
+
+    t.Run("a finished game can't be resumed", func(t *testing.T) {
+        server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, &GameSpy{GameID: "abc"}))
+        defer server.Close()
+
+        ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris"}})
+        session := readWSSession(t, ws)
+        writeWSMessage(t, ws, FinishMessage, FinishPayload{Winner: "Ruth"})
+        ws.ReadMessage()
+
+        again := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        defer again.Close()
+
+        writeWSMessage(t, again, ResumeMessage, ResumePayload{Token: session.Token})
+        within(t, 100*time.Millisecond, func() { assertWebsocketGotMsg(t, again, ErrorMessage, ErrorPayload{ErrUnknownSession.Error()}) })
+    })
+
+    t.Run("cancels the game's alerts when the dealer doesn't reconnect in time", func(t *testing.T) {
+        game := &GameSpy{}
+        playerServer := mustMakePlayerServer(t, dummyPlayerStore, game)
+        playerServer.resumeTimeout = 10 * time.Millisecond
+        server := httptest.NewServer(playerServer)
+        defer server.Close()
+
+        ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+        writeWSMessage(t, ws, StartMessage, StartPayload{Players: []string{"Ruth", "Chris", "Cleo"}})
+        readWSSession(t, ws)
+        ws.Close()
+
+        passed := retryUntil(500*time.Millisecond, func() bool {
+            game.Lock()
+            defer game.Unlock()
+            return game.StartedCtx != nil && game.StartedCtx.Err() != nil
+        })
+
+        if !passed {
+            t.Error("expected the game's context to be cancelled after the connection closed")
+        }
+        game.Lock()
+        defer game.Unlock()
+
+        if game.FinishedCalled {
+            t.Error("game should not have finished")
+        }
     })
 }
 
+func readWSSession(t testing.TB, ws *websocket.Conn) SessionPayload {
+    t.Helper()
+
+    ws.SetReadDeadline(time.Now().Add(time.Second))
+    defer ws.SetReadDeadline(time.Time{})
+
+    _, data, err := ws.ReadMessage()
+    if err != nil {
+        t.Fatalf("could not read from ws connection %v", err)
+    }
+
+    msg, err := DecodeMessage(data)
+    if err != nil || msg.Type != SessionMessage {
+        t.Fatalf("got %s, want a session message", data)
+    }
+
+    var session SessionPayload
+    if err := msg.DecodePayload(&session); err != nil {
+        t.Fatal(err)
+    }
+    return session
+}
+
+func getActiveGames(t testing.TB, server http.Handler) []ActiveGame {
+    t.Helper()
+
+    request, _ := http.NewRequest(http.MethodGet, "/games/active", nil)
+    response := httptest.NewRecorder()
+
+    server.ServeHTTP(response, request)
+
+    assertStatus(t, response, http.StatusOK)
+    assertContentType(t, response, jsonContentType)
+
+    var active []ActiveGame
+    decodeJSON(t, response.Body, &active)
+    return active
+}
+
+func assertActiveGames(t testing.TB, server http.Handler, wantIDs ...string) {
+    t.Helper()
+
+    var got []string
+    for _, game := range getActiveGames(t, server) {
+        got = append(got, game.ID)
+    }
+
+    if !reflect.DeepEqual(got, wantIDs) {
+        t.Errorf("got active games %v, want %v", got, wantIDs)
+    }
+}
+
+func newPostGameRequest(body string) *http.Request {
+    req, _ := http.NewRequest(http.MethodPost, "/games", strings.NewReader(body))
+    return req
+}
+
+func newPostFinishRequest(id, body string) *http.Request {
+    req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/games/%s/finish", id), strings.NewReader(body))
+    return req
+}
+
 func newGetScoreRequest(name string) *http.Request {
     req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/players/%s", name), nil)
     return req
@@ -145,6 +1327,11 @@ func newPostWinRequest(name string) *http.Request {
     return req
 }
 
+func newPlayerAdminRequest(method, path, body string) *http.Request {
+    req, _ := http.NewRequest(method, "/players/"+path, strings.NewReader(body))
+    return req
+}
+
 func newGameRequest() *http.Request {
     req, _ := http.NewRequest(http.MethodGet, "/game", nil)
     return req
@@ -175,6 +1362,13 @@ func getLeagueFromResponse(t testing.TB, body io.Reader) (league []Player) {
     return
 }
 
+func decodeJSON(t testing.TB, body io.Reader, v interface{}) {
+    t.Helper()
+    if err := json.NewDecoder(body).Decode(v); err != nil {
+        t.Fatalf("Unable to parse response from server into %T, '%v'", v, err)
+    }
+}
+
 func assertLeague(t testing.TB, got, want []Player) {
     t.Helper()
     if !reflect.DeepEqual(got, want) {
@@ -182,6 +1376,22 @@ func assertLeague(t testing.TB, got, want []Player) {
     }
 }
 
+func assertAPIError(t testing.TB, response *httptest.ResponseRecorder, status int, message string) {
+    t.Helper()
+
+    assertContentType(t, response, jsonContentType)
+
+    var got struct {
+        Error APIError `json:"error"`
+    }
+    decodeJSON(t, response.Body, &got)
+
+    want := APIError{status, message}
+    if got.Error != want {
+        t.Errorf("got error %+v, want %+v", got.Error, want)
+    }
+}
+
 func assertContentType(t testing.TB, response *httptest.ResponseRecorder, want string) {
     t.Helper()
     if response.Result().Header.Get("content-type") != want {
@@ -191,28 +1401,57 @@ func assertContentType(t testing.TB, response *httptest.ResponseRecorder, want s
 
 func assertGameStartedWith(t testing.TB, game *GameSpy, want int) {
     t.Helper()
-    if game.StartedWith != want {
+
+    passed := retryUntil(500*time.Millisecond, func() bool {
+        game.Lock()
+        defer game.Unlock()
+        return game.StartedWith == want
+    })
+
+    game.Lock()
+    defer game.Unlock()
+
+    if !passed {
         t.Errorf("game did not start with %d, got %d", want, game.StartedWith)
     }
-
 }
 
 func assertFinishCalledWith(t testing.TB, game *GameSpy, winner string) {
     t.Helper()
 
     passed := retryUntil(500*time.Millisecond, func() bool {
+        game.Lock()
+        defer game.Unlock()
         return game.FinishedWith == winner
     })
 
+    game.Lock()
+    defer game.Unlock()
+
     if !passed {
         t.Errorf("expected finish called with %q but got %q", winner, game.FinishedWith)
     }
 }
 
-func assertWebsocketGotMsg(t *testing.T, ws *websocket.Conn, want string) {
-    _, msg, _ := ws.ReadMessage()
-    if string(msg) != want {
-        t.Errorf(`got "%s", want "%s"`, string(msg), want)
+func assertWebsocketGotMsg(t testing.TB, ws *websocket.Conn, wantType MessageType, wantPayload interface{}) {
+    t.Helper()
+
+    _, data, err := ws.ReadMessage()
+    if err != nil {
+        t.Errorf("could not read from ws connection %v", err)
+        return
+    }
+
+    got, err := DecodeMessage(data)
+    if err != nil {
+        t.Errorf("got %s, %v", data, err)
+        return
+    }
+
+    want, _ := NewMessage(wantType, wantPayload)
+
+    if got.Type != want.Type || string(got.Payload) != string(want.Payload) {
+        t.Errorf("got %s %s, want %s %s", got.Type, got.Payload, want.Type, want.Payload)
     }
 }
 
@@ -222,7 +1461,7 @@ func newLeagueRequest() *http.Request {
 }
 
 func mustMakePlayerServer(t *testing.T, store PlayerStore, game Game) *PlayerServer {
-    server, err := NewPlayerServer(store, game)
+    server, err := NewPlayerServer(store, SameGame(game))
     if err != nil {
         t.Fatal("problem creating player server", err)
     }
@@ -239,9 +1478,15 @@ func mustDialWS(t *testing.T, url string) *websocket.Conn {
     return ws
 }
 
-func writeWSMessage(t testing.TB, conn *websocket.Conn, message string) {
+func writeWSMessage(t testing.TB, conn *websocket.Conn, msgType MessageType, payload interface{}) {
     t.Helper()
-    if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+
+    msg, err := NewMessage(msgType, payload)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if err := conn.WriteJSON(msg); err != nil {
         t.Fatalf("could not send message over ws connection %v", err)
     }
 }
@@ -0,0 +1,840 @@
+package poker
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// migrations are applied in order to bring a database up to date. The number
+// applied so far is kept in SQLite's user_version. Only ever add to the end.
+var migrations = []string{
+    `CREATE TABLE players (
+        name TEXT PRIMARY KEY,
+        wins INTEGER NOT NULL DEFAULT 0
+    );
+    CREATE TABLE games (
+        id                TEXT PRIMARY KEY,
+        started_at        TEXT NOT NULL,
+        finished_at       TEXT NOT NULL,
+        number_of_players INTEGER NOT NULL,
+        blind_structure   TEXT NOT NULL
+    );
+    CREATE TABLE game_players (
+        game_id TEXT NOT NULL REFERENCES games(id),
+        seat    INTEGER NOT NULL,
+        name    TEXT NOT NULL,
+        place   INTEGER,
+        PRIMARY KEY (game_id, seat)
+    );`,
+    `ALTER TABLE players ADD COLUMN played INTEGER NOT NULL DEFAULT 0;`,
+    `CREATE TABLE wins (
+        name   TEXT NOT NULL,
+        won_at TEXT NOT NULL
+    );`,
+    `CREATE TABLE rating_changes (
+        name          TEXT NOT NULL,
+        game_id       TEXT NOT NULL REFERENCES games(id),
+        finished_at   TEXT NOT NULL,
+        place         INTEGER NOT NULL,
+        rating_before REAL NOT NULL,
+        rating_after  REAL NOT NULL
+    );`,
+}
+
+// SQLPlayerStore keeps the league and game results in a SQLite database.
+type SQLPlayerStore struct {
+    db  *sql.DB
+    now func() time.Time
+}
+
+// NewSQLPlayerStore creates a store on db, migrating its schema to the
+// latest version. A database whose games were recorded before ratings were
+// kept has them worked out from its games.
+func NewSQLPlayerStore(db *sql.DB) (*SQLPlayerStore, error) {
+    // SQLite allows one writer at a time, and an in-memory database only
+    // lives as long as its connection.
+    db.SetMaxOpenConns(1)
+
+    if err := migrate(db); err != nil {
+        return nil, fmt.Errorf("problem migrating player database, %v", err)
+    }
+
+    if err := rateUnratedGames(db); err != nil {
+        return nil, fmt.Errorf("problem rating player database, %v", err)
+    }
+
+    return &SQLPlayerStore{db, time.Now}, nil
+}
+
+// rateUnratedGames rates every game if none have been rated.
+func rateUnratedGames(db *sql.DB) error {
+    var rated bool
+
+    if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM rating_changes)").Scan(&rated); err != nil || rated {
+        return err
+    }
+
+    tx, err := db.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if err := rerateGames(tx); err != nil {
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// SQLPlayerStoreFromFile opens, or creates, the SQLite database at path.
+func SQLPlayerStoreFromFile(path string) (*SQLPlayerStore, func(), error) {
+    db, err := sql.Open("sqlite", path)
+
+    if err != nil {
+        return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
+    }
+
+    closeFunc := func() {
+        db.Close()
+    }
+
+    store, err := NewSQLPlayerStore(db)
+
+    if err != nil {
+        db.Close()
+        return nil, nil, fmt.Errorf("problem creating sql player store, %v ", err)
+    }
+
+    return store, closeFunc, nil
+}
+
+func migrate(db *sql.DB) error {
+    var version int
+
+    if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+        return err
+    }
+
+    for i := version; i < len(migrations); i++ {
+        tx, err := db.Begin()
+        if err != nil {
+            return err
+        }
+
+        if _, err := tx.Exec(migrations[i]); err != nil {
+            tx.Rollback()
+            return fmt.Errorf("migration %d failed, %v", i+1, err)
+        }
+
+        if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+            tx.Rollback()
+            return err
+        }
+
+        if err := tx.Commit(); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+func (s *SQLPlayerStore) GetPlayer(name string) (Player, bool) {
+    player := Player{Name: name}
+
+    err := s.db.QueryRow("SELECT wins, played FROM players WHERE name = ?", name).Scan(&player.Wins, &player.Played)
+
+    if err != nil {
+        if err != sql.ErrNoRows {
+            log.Printf("problem getting player %s, %v\n", name, err)
+        }
+        return Player{}, false
+    }
+
+    return player, true
+}
+
+func (s *SQLPlayerStore) GetPlayerScore(name string) int {
+    var wins int
+
+    err := s.db.QueryRow("SELECT wins FROM players WHERE name = ?", name).Scan(&wins)
+
+    if err != nil && err != sql.ErrNoRows {
+        log.Printf("problem getting score for %s, %v\n", name, err)
+    }
+
+    return wins
+}
+
+func (s *SQLPlayerStore) RecordWin(name string) error {
+    tx, err := s.db.Begin()
+    if err != nil {
+        return fmt.Errorf("problem recording win for %s, %v", name, err)
+    }
+    defer tx.Rollback()
+
+    if err := recordWin(tx, name, s.now()); err != nil {
+        return fmt.Errorf("problem recording win for %s, %v", name, err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("problem recording win for %s, %v", name, err)
+    }
+
+    return nil
+}
+
+func recordWin(tx *sql.Tx, name string, at time.Time) error {
+    _, err := tx.Exec(`INSERT INTO players (name, wins) VALUES (?, 1)
+        ON CONFLICT (name) DO UPDATE SET wins = wins + 1`, name)
+
+    if err != nil {
+        return err
+    }
+
+    _, err = tx.Exec("INSERT INTO wins (name, won_at) VALUES (?, ?)", name, formatTime(at))
+    return err
+}
+
+// sqlTieBreaks are the ORDER BY clauses for players level on wins for the
+// tie-breaks the database works out itself, the zero TieBreak being the
+// default.
+var sqlTieBreaks = map[TieBreak]string{
+    "":             "joined",
+    TieBreakJoined: "joined",
+    TieBreakName:   "name, joined",
+}
+
+// GetLeague returns the part of the league asked for, ranked. The all-time
+// league is ranked, filtered, sorted and paged by the database when it can
+// break its ties. The league for a window is counted from the wins and games
+// in it, and ties broken by last win or head to head need them too, so those
+// are worked out here.
+func (s *SQLPlayerStore) GetLeague(query LeagueQuery) Standings {
+    tieBreak, found := sqlTieBreaks[query.TieBreak]
+    if !query.Window.IsAllTime() || !found {
+        return s.rankLeague(query)
+    }
+
+    order := "wins DESC, " + tieBreak
+    if query.Order == ByName {
+        order = "name, joined"
+    }
+
+    // SQLite takes a negative limit as no limit
+    limit := query.Limit
+    if limit == 0 {
+        limit = -1
+    }
+
+    standings, err := s.queryLeague(`SELECT name, wins, played, rank FROM (
+            SELECT name, wins, played, rowid AS joined, RANK() OVER (ORDER BY wins DESC) AS rank FROM players
+        )
+        WHERE substr(name, 1, length(?1)) = ?1
+        ORDER BY `+order+` LIMIT ?2 OFFSET ?3`,
+        query.NamePrefix, limit, query.Offset)
+
+    if err != nil {
+        log.Printf("problem getting league, %v\n", err)
+        return Standings{}
+    }
+
+    return standings
+}
+
+// GetLeagueIn returns the league counting only the wins and games in window,
+// ranked by wins.
+func (s *SQLPlayerStore) GetLeagueIn(window Window) League {
+    return s.GetLeague(LeagueQuery{Window: window}).Players()
+}
+
+func (s *SQLPlayerStore) rankLeague(query LeagueQuery) Standings {
+    players, err := s.queryLeague("SELECT name, wins, played, 0 FROM players ORDER BY rowid")
+    if err != nil {
+        log.Printf("problem getting league, %v\n", err)
+        return Standings{}
+    }
+
+    wins, err := s.getWins()
+    if err != nil {
+        log.Printf("problem getting wins, %v\n", err)
+        return Standings{}
+    }
+
+    games := s.GetGames()
+
+    league := players.Players()
+    if !query.Window.IsAllTime() {
+        league = league.in(query.Window, wins, games)
+    }
+    return league.query(query, wins, games)
+}
+
+// queryLeague reads the name, wins, games played and rank of each player
+// query selects.
+func (s *SQLPlayerStore) queryLeague(query string, args ...interface{}) (Standings, error) {
+    rows, err := s.db.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    standings := Standings{}
+
+    for rows.Next() {
+        var standing Standing
+        if err := rows.Scan(&standing.Name, &standing.Wins, &standing.Played, &standing.Rank); err != nil {
+            return nil, err
+        }
+        standings = append(standings, standing)
+    }
+
+    return standings, rows.Err()
+}
+
+func (s *SQLPlayerStore) getWins() ([]Win, error) {
+    rows, err := s.db.Query("SELECT name, won_at FROM wins ORDER BY rowid")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var wins []Win
+
+    for rows.Next() {
+        var win Win
+        var wonAt string
+
+        if err := rows.Scan(&win.Name, &wonAt); err != nil {
+            return nil, err
+        }
+
+        if win.At, err = parseTime(wonAt); err != nil {
+            return nil, err
+        }
+
+        wins = append(wins, win)
+    }
+
+    return wins, rows.Err()
+}
+
+// RecordGame stores the result of a finished game, giving it an ID if it
+// doesn't have one, and adds a game played for everyone who played in it.
+func (s *SQLPlayerStore) RecordGame(game GameRecord) (GameRecord, error) {
+    return s.recordGame(game, false)
+}
+
+// RecordResult records a finished game as RecordGame does and a win for its
+// winner as RecordWin does, in one transaction.
+func (s *SQLPlayerStore) RecordResult(game GameRecord) (GameRecord, error) {
+    return s.recordGame(game, true)
+}
+
+func (s *SQLPlayerStore) recordGame(game GameRecord, withWin bool) (GameRecord, error) {
+    if game.ID == "" {
+        game.ID = newGameID()
+    }
+
+    tx, err := s.db.Begin()
+    if err != nil {
+        return GameRecord{}, fmt.Errorf("problem recording game %s, %v", game.ID, err)
+    }
+    defer tx.Rollback()
+
+    if winner := game.Winner(); withWin && winner != "" {
+        if err := recordWin(tx, winner, s.now()); err != nil {
+            return GameRecord{}, fmt.Errorf("problem recording win for %s, %v", winner, err)
+        }
+    }
+
+    _, err = tx.Exec(`INSERT INTO games (id, started_at, finished_at, number_of_players, blind_structure)
+        VALUES (?, ?, ?, ?, ?)`,
+        game.ID, formatTime(game.StartedAt), formatTime(game.FinishedAt), game.NumberOfPlayers, game.BlindStructure)
+
+    if err != nil {
+        return GameRecord{}, fmt.Errorf("problem recording game %s, %v", game.ID, err)
+    }
+
+    if err := insertGamePlayers(tx, game); err != nil {
+        return GameRecord{}, fmt.Errorf("problem recording players of game %s, %v", game.ID, err)
+    }
+
+    if err := rateGame(tx, game); err != nil {
+        return GameRecord{}, fmt.Errorf("problem rating game %s, %v", game.ID, err)
+    }
+
+    for _, name := range gamePlayers(game) {
+        _, err = tx.Exec(`INSERT INTO players (name, played) VALUES (?, 1)
+            ON CONFLICT (name) DO UPDATE SET played = played + 1`, name)
+
+        if err != nil {
+            return GameRecord{}, fmt.Errorf("problem recording players of game %s, %v", game.ID, err)
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return GameRecord{}, fmt.Errorf("problem recording game %s, %v", game.ID, err)
+    }
+
+    return game, nil
+}
+
+// GetGames returns every game recorded, oldest first.
+func (s *SQLPlayerStore) GetGames() []GameRecord {
+    games, err := queryGames(s.db, "SELECT id, started_at, finished_at, number_of_players, blind_structure FROM games ORDER BY rowid")
+
+    if err != nil {
+        log.Printf("problem getting games, %v\n", err)
+        return []GameRecord{}
+    }
+
+    return games
+}
+
+func (s *SQLPlayerStore) GetGame(id string) (GameRecord, bool) {
+    games, err := queryGames(s.db, "SELECT id, started_at, finished_at, number_of_players, blind_structure FROM games WHERE id = ?", id)
+
+    if err != nil {
+        log.Printf("problem getting game %s, %v\n", id, err)
+        return GameRecord{}, false
+    }
+
+    if len(games) == 0 {
+        return GameRecord{}, false
+    }
+
+    return games[0], true
+}
+
+func queryGames(q querier, query string, args ...interface{}) ([]GameRecord, error) {
+    rows, err := q.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+
+    games := []GameRecord{}
+
+    for rows.Next() {
+        var game GameRecord
+        var startedAt, finishedAt string
+
+        if err := rows.Scan(&game.ID, &startedAt, &finishedAt, &game.NumberOfPlayers, &game.BlindStructure); err != nil {
+            rows.Close()
+            return nil, err
+        }
+
+        if game.StartedAt, err = parseTime(startedAt); err != nil {
+            rows.Close()
+            return nil, err
+        }
+
+        if game.FinishedAt, err = parseTime(finishedAt); err != nil {
+            rows.Close()
+            return nil, err
+        }
+
+        games = append(games, game)
+    }
+
+    rows.Close()
+
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    for i := range games {
+        if err := loadGamePlayers(q, &games[i]); err != nil {
+            return nil, err
+        }
+    }
+
+    return games, nil
+}
+
+// GetRatings returns everyone's ratings.
+func (s *SQLPlayerStore) GetRatings() Ratings {
+    rows, err := s.db.Query(`SELECT name, game_id, finished_at, place, rating_before, rating_after
+        FROM rating_changes ORDER BY rowid`)
+
+    if err != nil {
+        log.Printf("problem getting ratings, %v\n", err)
+        return Ratings{}
+    }
+    defer rows.Close()
+
+    ratings := Ratings{}
+
+    for rows.Next() {
+        var name, finishedAt string
+        var change RatingChange
+
+        if err := rows.Scan(&name, &change.GameID, &finishedAt, &change.Place, &change.Before, &change.After); err != nil {
+            log.Printf("problem getting ratings, %v\n", err)
+            return Ratings{}
+        }
+
+        if change.FinishedAt, err = parseTime(finishedAt); err != nil {
+            log.Printf("problem getting ratings, %v\n", err)
+            return Ratings{}
+        }
+
+        rating := ratings.Get(name)
+        rating.Rating = change.After
+        rating.History = append(rating.History, change)
+        ratings[name] = rating
+    }
+
+    if err := rows.Err(); err != nil {
+        log.Printf("problem getting ratings, %v\n", err)
+        return Ratings{}
+    }
+
+    return ratings
+}
+
+// rateGame stores how game moved the ratings of everyone who played in it,
+// from where their last game left them.
+func rateGame(tx *sql.Tx, game GameRecord) error {
+    ratings := Ratings{}
+
+    for _, name := range gamePlayers(game) {
+        var rating float64
+
+        err := tx.QueryRow("SELECT rating_after FROM rating_changes WHERE name = ? ORDER BY rowid DESC LIMIT 1", name).Scan(&rating)
+
+        switch {
+        case err == sql.ErrNoRows:
+        case err != nil:
+            return err
+        default:
+            ratings[name] = PlayerRating{Name: name, Rating: rating}
+        }
+    }
+
+    ratings.rate(game)
+    return insertRatingChanges(tx, ratings)
+}
+
+// rerateGames replaces everyone's ratings with those of replaying every game,
+// see RateGames.
+func rerateGames(tx *sql.Tx) error {
+    games, err := queryGames(tx, "SELECT id, started_at, finished_at, number_of_players, blind_structure FROM games ORDER BY rowid")
+    if err != nil {
+        return err
+    }
+
+    if _, err := tx.Exec("DELETE FROM rating_changes"); err != nil {
+        return err
+    }
+
+    return insertRatingChanges(tx, RateGames(games))
+}
+
+func insertRatingChanges(tx *sql.Tx, ratings Ratings) error {
+    for name, rating := range ratings {
+        for _, change := range rating.History {
+            _, err := tx.Exec(`INSERT INTO rating_changes (name, game_id, finished_at, place, rating_before, rating_after)
+                VALUES (?, ?, ?, ?, ?, ?)`,
+                name, change.GameID, formatTime(change.FinishedAt), change.Place, change.Before, change.After)
+
+            if err != nil {
+                return err
+            }
+        }
+    }
+
+    return nil
+}
+
+// RenamePlayer renames the player named from to, in the league and in the
+// games they played.
+func (s *SQLPlayerStore) RenamePlayer(from, to string) error {
+    tx, err := s.db.Begin()
+    if err != nil {
+        return fmt.Errorf("problem renaming %s, %v", from, err)
+    }
+    defer tx.Rollback()
+
+    if err := renamePlayer(tx, from, to); err != nil {
+        return err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("problem renaming %s, %v", from, err)
+    }
+
+    return nil
+}
+
+// MergePlayers adds the wins and games of the player named from to the
+// player named into, and removes from. Merging into a player who isn't in
+// the league renames from.
+func (s *SQLPlayerStore) MergePlayers(from, into string) error {
+    if from == into {
+        return fmt.Errorf("%w, %q", ErrSamePlayer, from)
+    }
+
+    tx, err := s.db.Begin()
+    if err != nil {
+        return fmt.Errorf("problem merging %s into %s, %v", from, into, err)
+    }
+    defer tx.Rollback()
+
+    if err := mergePlayers(tx, from, into); err != nil {
+        return err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("problem merging %s into %s, %v", from, into, err)
+    }
+
+    return nil
+}
+
+func renamePlayer(tx *sql.Tx, from, to string) error {
+    if strings.TrimSpace(to) == "" {
+        return ErrNoPlayerName
+    }
+
+    if _, _, err := findPlayer(tx, from); err != nil {
+        return err
+    }
+
+    if from == to {
+        return nil
+    }
+
+    if _, _, err := findPlayer(tx, to); err == nil {
+        return fmt.Errorf("%w %q", ErrPlayerExists, to)
+    } else if !errors.Is(err, ErrPlayerNotFound) {
+        return err
+    }
+
+    if _, err := tx.Exec("UPDATE players SET name = ? WHERE name = ?", to, from); err != nil {
+        return fmt.Errorf("problem renaming %s, %v", from, err)
+    }
+
+    if _, err := tx.Exec("UPDATE wins SET name = ? WHERE name = ?", to, from); err != nil {
+        return fmt.Errorf("problem renaming %s, %v", from, err)
+    }
+
+    if _, err := renameInGamePlayers(tx, from, to); err != nil {
+        return fmt.Errorf("problem renaming %s in their games, %v", from, err)
+    }
+
+    if err := rerateGames(tx); err != nil {
+        return fmt.Errorf("problem rating the games of %s, %v", from, err)
+    }
+
+    return nil
+}
+
+func mergePlayers(tx *sql.Tx, from, into string) error {
+    if _, _, err := findPlayer(tx, into); errors.Is(err, ErrPlayerNotFound) {
+        return renamePlayer(tx, from, into)
+    } else if err != nil {
+        return err
+    }
+
+    wins, played, err := findPlayer(tx, from)
+    if err != nil {
+        return err
+    }
+
+    shared, err := renameInGamePlayers(tx, from, into)
+    if err != nil {
+        return fmt.Errorf("problem merging the games of %s into %s, %v", from, into, err)
+    }
+
+    if _, err := tx.Exec("DELETE FROM players WHERE name = ?", from); err != nil {
+        return fmt.Errorf("problem merging %s into %s, %v", from, into, err)
+    }
+
+    if _, err := tx.Exec("UPDATE wins SET name = ? WHERE name = ?", into, from); err != nil {
+        return fmt.Errorf("problem merging %s into %s, %v", from, into, err)
+    }
+
+    _, err = tx.Exec("UPDATE players SET wins = wins + ?, played = played + ? WHERE name = ?",
+        wins, played-shared, into)
+
+    if err != nil {
+        return fmt.Errorf("problem merging %s into %s, %v", from, into, err)
+    }
+
+    if err := rerateGames(tx); err != nil {
+        return fmt.Errorf("problem rating the games of %s and %s, %v", from, into, err)
+    }
+
+    return nil
+}
+
+// DeletePlayer removes the player from the league. The games they played are
+// kept as they were.
+func (s *SQLPlayerStore) DeletePlayer(name string) error {
+    tx, err := s.db.Begin()
+    if err != nil {
+        return fmt.Errorf("problem deleting %s, %v", name, err)
+    }
+    defer tx.Rollback()
+
+    result, err := tx.Exec("DELETE FROM players WHERE name = ?", name)
+    if err != nil {
+        return fmt.Errorf("problem deleting %s, %v", name, err)
+    }
+
+    if deleted, err := result.RowsAffected(); err != nil {
+        return fmt.Errorf("problem deleting %s, %v", name, err)
+    } else if deleted == 0 {
+        return fmt.Errorf("%w %q", ErrPlayerNotFound, name)
+    }
+
+    if _, err := tx.Exec("DELETE FROM wins WHERE name = ?", name); err != nil {
+        return fmt.Errorf("problem deleting %s, %v", name, err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("problem deleting %s, %v", name, err)
+    }
+
+    return nil
+}
+
+// findPlayer returns the wins and games played of the player named name, or
+// an ErrPlayerNotFound.
+func findPlayer(tx *sql.Tx, name string) (wins, played int, err error) {
+    err = tx.QueryRow("SELECT wins, played FROM players WHERE name = ?", name).Scan(&wins, &played)
+
+    switch {
+    case err == sql.ErrNoRows:
+        return 0, 0, fmt.Errorf("%w %q", ErrPlayerNotFound, name)
+    case err != nil:
+        return 0, 0, fmt.Errorf("problem getting player %s, %v", name, err)
+    }
+
+    return wins, played, nil
+}
+
+// renameInGamePlayers rewrites the players of every game the player named
+// from played in with them renamed to, returning how many of those games to
+// also played in.
+func renameInGamePlayers(tx *sql.Tx, from, to string) (shared int, err error) {
+    rows, err := tx.Query("SELECT DISTINCT game_id FROM game_players WHERE name = ?", from)
+    if err != nil {
+        return 0, err
+    }
+
+    var ids []string
+
+    for rows.Next() {
+        var id string
+        if err := rows.Scan(&id); err != nil {
+            rows.Close()
+            return 0, err
+        }
+        ids = append(ids, id)
+    }
+
+    rows.Close()
+
+    if err := rows.Err(); err != nil {
+        return 0, err
+    }
+
+    for _, id := range ids {
+        game := GameRecord{ID: id}
+
+        if err := loadGamePlayers(tx, &game); err != nil {
+            return 0, err
+        }
+
+        if containsName(gamePlayers(game), to) {
+            shared++
+        }
+
+        if _, err := tx.Exec("DELETE FROM game_players WHERE game_id = ?", id); err != nil {
+            return 0, err
+        }
+
+        if err := insertGamePlayers(tx, game.withPlayerRenamed(from, to)); err != nil {
+            return 0, err
+        }
+    }
+
+    return shared, nil
+}
+
+// insertGamePlayers stores who played in game, seated in the order they
+// played, with the places they finished in.
+func insertGamePlayers(tx *sql.Tx, game GameRecord) error {
+    for seat, name := range gamePlayers(game) {
+        var place sql.NullInt64
+
+        for i, placed := range game.FinishingOrder {
+            if placed == name {
+                place = sql.NullInt64{Int64: int64(i + 1), Valid: true}
+                break
+            }
+        }
+
+        _, err := tx.Exec("INSERT INTO game_players (game_id, seat, name, place) VALUES (?, ?, ?, ?)",
+            game.ID, seat, name, place)
+
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// querier is a *sql.DB or a *sql.Tx.
+type querier interface {
+    Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func loadGamePlayers(q querier, game *GameRecord) error {
+    rows, err := q.Query("SELECT name, place FROM game_players WHERE game_id = ? ORDER BY seat", game.ID)
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+
+    placed := map[int64]string{}
+
+    for rows.Next() {
+        var name string
+        var place sql.NullInt64
+
+        if err := rows.Scan(&name, &place); err != nil {
+            return err
+        }
+
+        game.Participants = append(game.Participants, name)
+
+        if place.Valid {
+            placed[place.Int64] = name
+        }
+    }
+
+    for place := int64(1); place <= int64(len(placed)); place++ {
+        game.FinishingOrder = append(game.FinishingOrder, placed[place])
+    }
+
+    return rows.Err()
+}
+
+func formatTime(t time.Time) string {
+    return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) (time.Time, error) {
+    return time.Parse(time.RFC3339Nano, s)
+}
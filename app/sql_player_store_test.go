@@ -0,0 +1,128 @@
+package poker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLPlayerStore(t *testing.T) {
+    t.Run("keeps participants who weren't placed", func(t *testing.T) {
+        path := filepath.Join(t.TempDir(), "game.db")
+        store := newTestSQLPlayerStore(t, path)
+
+        recordWins(t, store, "Cleo", 1)
+
+        played := GameRecord{
+            StartedAt:       time.Date(2021, 2, 18, 20, 0, 0, 0, time.UTC),
+            FinishedAt:      time.Date(2021, 2, 18, 22, 0, 0, 0, time.UTC),
+            NumberOfPlayers: 3,
+            Participants:    []string{"Cleo", "Chris", "Ruth"},
+            FinishingOrder:  []string{"Cleo", "Chris"},
+            BlindStructure:  "default",
+        }
+
+        recorded, err := store.RecordGame(played)
+        assertNoError(t, err)
+        played.ID = recorded.ID
+
+        reopened := newTestSQLPlayerStore(t, path)
+
+        got, found := reopened.GetGame(played.ID)
+
+        if !found {
+            t.Fatalf("game %s not found after reopening the store", played.ID)
+        }
+
+        assertGameRecord(t, got, played)
+        assertScoreEquals(t, reopened.GetPlayerScore("Cleo"), 1)
+
+        if len(reopened.GetGames()) != 1 {
+            t.Errorf("got %d games, want 1", len(reopened.GetGames()))
+        }
+    })
+
+    t.Run("rates the games of databases written before ratings were kept", func(t *testing.T) {
+        path := filepath.Join(t.TempDir(), "game.db")
+        store := newTestSQLPlayerStore(t, path)
+
+        _, err := store.RecordGame(GameRecord{Participants: []string{"Cleo", "Chris"}, FinishingOrder: []string{"Cleo", "Chris"}})
+        assertNoError(t, err)
+
+        _, err = store.db.Exec("DELETE FROM rating_changes")
+        assertNoError(t, err)
+
+        reopened := newTestSQLPlayerStore(t, path)
+
+        if got := reopened.GetRatings().Get("Cleo"); len(got.History) != 1 || got.Rating <= InitialRating {
+            t.Errorf("got Cleo rated %+v, want Cleo's rating up after winning", got)
+        }
+    })
+}
+
+func TestOpenPlayerStore(t *testing.T) {
+    dir := t.TempDir()
+
+    cases := map[string]interface{}{
+        filepath.Join(dir, "game.db.json"):           &FileSystemPlayerStore{},
+        "json:" + filepath.Join(dir, "other.db.json"): &FileSystemPlayerStore{},
+        "sqlite:" + filepath.Join(dir, "game.db"):     &SQLPlayerStore{},
+        "memory":                                      &InMemoryPlayerStore{},
+        "memory:" + filepath.Join(dir, "night.json"):  &InMemoryPlayerStore{},
+    }
+
+    for spec, want := range cases {
+        t.Run(spec, func(t *testing.T) {
+            store, closeStore, err := OpenPlayerStore(spec)
+            assertNoError(t, err)
+            defer closeStore()
+
+            if got, want := typeName(store), typeName(want); got != want {
+                t.Errorf("got a %s, want a %s", got, want)
+            }
+        })
+    }
+
+    t.Run("paths with colons that aren't a kind of store are JSON files", func(t *testing.T) {
+        for _, spec := range []string{
+            filepath.Join(dir, "C:night.json"),
+            "json:" + filepath.Join(dir, "C:other.json"),
+        } {
+            store, closeStore, err := OpenPlayerStore(spec)
+            assertNoError(t, err)
+            closeStore()
+
+            if got, want := typeName(store), typeName(&FileSystemPlayerStore{}); got != want {
+                t.Errorf("got a %s for %q, want a %s", got, spec, want)
+            }
+        }
+
+        if _, err := os.Stat(filepath.Join(dir, "C:other.json")); err != nil {
+            t.Errorf("expected the file to keep its colon, %v", err)
+        }
+    })
+}
+
+func newTestSQLPlayerStore(t testing.TB, path string) *SQLPlayerStore {
+    t.Helper()
+
+    store, closeStore, err := SQLPlayerStoreFromFile(path)
+    assertNoError(t, err)
+    t.Cleanup(closeStore)
+
+    return store
+}
+
+func recordWins(t testing.TB, store PlayerStore, name string, wins int) {
+    t.Helper()
+
+    for i := 0; i < wins; i++ {
+        assertNoError(t, store.RecordWin(name))
+    }
+}
+
+func typeName(v interface{}) string {
+    return fmt.Sprintf("%T", v)
+}
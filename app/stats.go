@@ -0,0 +1,108 @@
+package poker
+
+import (
+	"sort"
+	"time"
+)
+
+// PlayerStats sums up the games a player has played. GameWins counts only
+// the recorded games they won, unlike their wins in the league which also
+// count wins recorded on their own, see Player. Streaks are games won in a
+// row, the current one ending with their last game. LastPlayed is nil if they
+// have never played.
+type PlayerStats struct {
+    Name             string
+    GameWins         int
+    Played           int
+    WinRate          float64
+    CurrentStreak    int
+    LongestStreak    int
+    AverageFieldSize float64
+    LastPlayed       *time.Time
+    HeadToHead       []HeadToHead
+}
+
+// HeadToHead is how a player has done against one opponent in the games
+// they played together: Wins where they finished above them, Losses below,
+// and Ties where neither was placed.
+type HeadToHead struct {
+    Opponent string
+    Played   int
+    Wins     int
+    Losses   int
+    Ties     int
+}
+
+// StatsFor works out the stats of the player named name from games, which
+// are in the order they were played. Head to head records are by opponent
+// name.
+func StatsFor(name string, games []GameRecord) PlayerStats {
+    stats := PlayerStats{Name: name, HeadToHead: []HeadToHead{}}
+    opponents := map[string]*HeadToHead{}
+    fieldSizes := 0
+
+    for _, game := range games {
+        players := gamePlayers(game)
+
+        if !containsName(players, name) {
+            continue
+        }
+
+        stats.Played++
+        fieldSizes += len(players)
+
+        if stats.LastPlayed == nil || game.FinishedAt.After(*stats.LastPlayed) {
+            finishedAt := game.FinishedAt
+            stats.LastPlayed = &finishedAt
+        }
+
+        if game.Winner() == name {
+            stats.GameWins++
+            stats.CurrentStreak++
+        } else {
+            stats.CurrentStreak = 0
+        }
+
+        if stats.CurrentStreak > stats.LongestStreak {
+            stats.LongestStreak = stats.CurrentStreak
+        }
+
+        for _, opponent := range players {
+            if opponent == name {
+                continue
+            }
+
+            record, found := opponents[opponent]
+            if !found {
+                record = &HeadToHead{Opponent: opponent}
+                opponents[opponent] = record
+            }
+
+            record.Played++
+
+            switch matchScore(game, name, opponent) {
+            case 1:
+                record.Wins++
+            case 0:
+                record.Losses++
+            default:
+                record.Ties++
+            }
+        }
+    }
+
+    if stats.Played > 0 {
+        stats.WinRate = float64(stats.GameWins) / float64(stats.Played)
+        stats.AverageFieldSize = float64(fieldSizes) / float64(stats.Played)
+    }
+
+    for _, record := range opponents {
+        stats.HeadToHead = append(stats.HeadToHead, *record)
+    }
+
+    sort.Slice(stats.HeadToHead, func(i, j int) bool {
+        return stats.HeadToHead[i].Opponent < stats.HeadToHead[j].Opponent
+    })
+
+    return stats
+}
@@ -0,0 +1,72 @@
+package poker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+    night := func(day int) time.Time {
+        return time.Date(2026, time.July, day, 22, 0, 0, 0, time.UTC)
+    }
+
+    games := []GameRecord{
+        {ID: "1", FinishedAt: night(1), Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Chris", "Cleo"}},
+        {ID: "2", FinishedAt: night(2), Participants: []string{"Chris", "Cleo", "Ruth"}, FinishingOrder: []string{"Chris"}},
+        {ID: "3", FinishedAt: night(3), Participants: []string{"Cleo", "Ruth"}, FinishingOrder: []string{"Ruth", "Cleo"}},
+        {ID: "4", FinishedAt: night(4), Participants: []string{"Chris", "Ruth", "Cleo"}, FinishingOrder: []string{"Ruth", "Chris", "Cleo"}},
+        {ID: "5", FinishedAt: night(5), Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Chris", "Cleo"}},
+    }
+
+    t.Run("sums up the games a player played", func(t *testing.T) {
+        got := StatsFor("Chris", games)
+        lastPlayed := night(5)
+
+        want := PlayerStats{
+            Name:             "Chris",
+            GameWins:         3,
+            Played:           4,
+            WinRate:          0.75,
+            CurrentStreak:    1,
+            LongestStreak:    2,
+            AverageFieldSize: 2.5,
+            LastPlayed:       &lastPlayed,
+            HeadToHead: []HeadToHead{
+                {Opponent: "Cleo", Played: 4, Wins: 4},
+                {Opponent: "Ruth", Played: 2, Wins: 1, Losses: 1},
+            },
+        }
+
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v\nwant %+v", got, want)
+        }
+    })
+
+    t.Run("players who weren't placed tie", func(t *testing.T) {
+        got := StatsFor("Cleo", games)
+
+        want := []HeadToHead{
+            {Opponent: "Chris", Played: 4, Losses: 4},
+            {Opponent: "Ruth", Played: 3, Losses: 2, Ties: 1},
+        }
+
+        if !reflect.DeepEqual(got.HeadToHead, want) {
+            t.Errorf("got %+v, want %+v", got.HeadToHead, want)
+        }
+
+        if got.CurrentStreak != 0 || got.LongestStreak != 0 {
+            t.Errorf("got streaks %d and %d, want none", got.CurrentStreak, got.LongestStreak)
+        }
+    })
+
+    t.Run("players who haven't played have empty stats", func(t *testing.T) {
+        got := StatsFor("Floyd", games)
+
+        want := PlayerStats{Name: "Floyd", HeadToHead: []HeadToHead{}}
+
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+}
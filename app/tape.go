@@ -1,16 +1,76 @@
 package poker
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
 
+// tape replaces the whole contents of the file at path with each write. The
+// new contents go to a temporary file next to it which is synced and renamed
+// over the file, so a failed write leaves the previous contents in place. The
+// file keeps its permissions.
 type tape struct {
-    file *os.File
+    path       string
+    createTemp func(dir, pattern string) (*os.File, error)
+}
+
+func newTape(path string) *tape {
+    return &tape{path: path, createTemp: ioutil.TempFile}
 }
 
 func (t *tape) Write(p []byte) (n int, err error) {
-	t.file.Truncate(0)
-    t.file.Seek(0, 0)
-    return t.file.Write(p)
-}
\ No newline at end of file
+    dir := filepath.Dir(t.path)
+
+    tmp, err := t.createTemp(dir, filepath.Base(t.path)+".*.tmp")
+
+    if err != nil {
+        return 0, fmt.Errorf("problem creating temp file in %s, %v", dir, err)
+    }
+
+    defer func() {
+        if err != nil {
+            tmp.Close()
+            os.Remove(tmp.Name())
+        }
+    }()
+
+    if info, statErr := os.Stat(t.path); statErr == nil {
+        if err = tmp.Chmod(info.Mode().Perm()); err != nil {
+            return 0, fmt.Errorf("problem setting the permissions of %s, %v", tmp.Name(), err)
+        }
+    }
+
+    if n, err = tmp.Write(p); err != nil {
+        return 0, fmt.Errorf("problem writing %s, %v", tmp.Name(), err)
+    }
+
+    if err = tmp.Sync(); err != nil {
+        return 0, fmt.Errorf("problem syncing %s, %v", tmp.Name(), err)
+    }
+
+    if err = tmp.Close(); err != nil {
+        return 0, fmt.Errorf("problem closing %s, %v", tmp.Name(), err)
+    }
+
+    if err = os.Rename(tmp.Name(), t.path); err != nil {
+        return 0, fmt.Errorf("problem replacing %s, %v", t.path, err)
+    }
+
+    syncDir(dir)
+
+    return n, nil
+}
+
+// syncDir makes the rename of a file in dir durable. Not every platform can
+// sync a directory, so failing to is not an error.
+func syncDir(dir string) {
+    d, err := os.Open(dir)
+    if err != nil {
+        return
+    }
+    d.Sync()
+    d.Close()
+}
@@ -1,25 +1,111 @@
 package poker
 
 import (
+	"errors"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
 func TestTape_Write(t *testing.T) {
-	file, clean := createTempFile(t, "12345")
-	defer clean()
+	t.Run("replaces the file's contents", func(t *testing.T) {
+		file, clean := createTempFile(t, "12345")
+		defer clean()
 
-	tape := &tape{file}
+		tape := newTape(file.Name())
 
-	tape.Write([]byte("abc"))
+		_, err := tape.Write([]byte("abc"))
+		assertNoError(t, err)
 
-	file.Seek(0, 0)
-	newFileContents, _ := ioutil.ReadAll(file)
+		assertFileContents(t, file.Name(), "abc")
+	})
 
-	got := string(newFileContents)
-	want := "abc"
+	t.Run("keeps the file's permissions", func(t *testing.T) {
+		file, clean := createTempFile(t, "12345")
+		defer clean()
 
-	if got != want {
+		assertNoError(t, os.Chmod(file.Name(), 0644))
+
+		tape := newTape(file.Name())
+
+		_, err := tape.Write([]byte("abc"))
+		assertNoError(t, err)
+
+		info, err := os.Stat(file.Name())
+		assertNoError(t, err)
+
+		if got := info.Mode().Perm(); got != 0644 {
+			t.Errorf("got permissions %v want %v", got, os.FileMode(0644))
+		}
+	})
+
+	t.Run("leaves the previous contents when the write fails", func(t *testing.T) {
+		file, clean := createTempFile(t, "12345")
+		defer clean()
+
+		tape := newTape(file.Name())
+		tape.createTemp = failingTempFile(t)
+
+		_, err := tape.Write([]byte("abc"))
+
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+
+		assertFileContents(t, file.Name(), "12345")
+		assertNoTempFilesLeft(t, file.Name())
+	})
+
+	t.Run("reports when the temp file can't be created", func(t *testing.T) {
+		file, clean := createTempFile(t, "12345")
+		defer clean()
+
+		tape := newTape(file.Name())
+		tape.createTemp = func(dir, pattern string) (*os.File, error) {
+			return nil, errors.New("disk full")
+		}
+
+		_, err := tape.Write([]byte("abc"))
+
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+
+		assertFileContents(t, file.Name(), "12345")
+	})
+}
+
+// failingTempFile creates temp files that are already closed, so writing to
+// them fails like writing to a full disk would.
+func failingTempFile(t testing.TB) func(dir, pattern string) (*os.File, error) {
+	return func(dir, pattern string) (*os.File, error) {
+		tmp, err := ioutil.TempFile(dir, pattern)
+		if err != nil {
+			t.Fatalf("could not create temp file %v", err)
+		}
+		tmp.Close()
+		return tmp, nil
+	}
+}
+
+func assertFileContents(t testing.TB, path, want string) {
+	t.Helper()
+
+	contents, err := ioutil.ReadFile(path)
+	assertNoError(t, err)
+
+	if got := string(contents); got != want {
 		t.Errorf("got %q want %q", got, want)
 	}
-}
\ No newline at end of file
+}
+
+func assertNoTempFilesLeft(t testing.TB, path string) {
+	t.Helper()
+
+	leftovers, _ := filepath.Glob(path + ".*.tmp")
+
+	if len(leftovers) > 0 {
+		t.Errorf("temp files were left behind %v", leftovers)
+	}
+}
@@ -1,18 +1,29 @@
 package poker
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"sync"
 	"testing"
 	"time"
 )
 
 var DummyGame = &GameSpy{}
 
+// SameGame makes every table on a PlayerServer play game, for tests that
+// play one game at a time.
+func SameGame(game Game) func() Game {
+    return func() Game { return game }
+}
+
+// ScheduledAlert is an alert a SpyBlindAlerter was asked to schedule. Amount
+// is its small blind, the whole alert is in Alert.
 type ScheduledAlert struct {
 	At     time.Duration
 	Amount int
     To io.Writer
+    Alert  BlindAlert
 }
 
 func (s ScheduledAlert) String() string {
@@ -21,16 +32,25 @@ func (s ScheduledAlert) String() string {
 
 type SpyBlindAlerter struct {
 	alerts []ScheduledAlert
+	ctx    context.Context
 }
 
-func (s *SpyBlindAlerter) ScheduleAlertAt(at time.Duration, amount int, to io.Writer) {
-	s.alerts = append(s.alerts, ScheduledAlert{at, amount, to})
+func (s *SpyBlindAlerter) ScheduleAlertAt(ctx context.Context, at time.Duration, alert BlindAlert, to io.Writer) {
+	s.ctx = ctx
+	s.alerts = append(s.alerts, ScheduledAlert{at, alert.SmallBlind, to, alert})
 }
 
 type StubPlayerStore struct {
     scores map[string]int
 	winCalls []string
 	league   []Player
+	games    []GameRecord
+	winErr   error
+}
+
+func (s *StubPlayerStore) GetPlayer(name string) (Player, bool) {
+    score, found := s.scores[name]
+    return Player{Name: name, Wins: score}, found
 }
 
 func (s *StubPlayerStore) GetPlayerScore(name string) int {
@@ -38,31 +58,161 @@ func (s *StubPlayerStore) GetPlayerScore(name string) int {
     return score
 }
 
-func (s *StubPlayerStore) RecordWin(name string) {
+func (s *StubPlayerStore) RecordWin(name string) error {
+    if s.winErr != nil {
+        return s.winErr
+    }
     s.winCalls = append(s.winCalls, name)
+    return nil
+}
+
+// GetLeague returns the stub's league as it is, ranked in that order.
+func (s *StubPlayerStore) GetLeague(query LeagueQuery) Standings {
+    standings := Standings{}
+
+    for i, player := range s.league {
+        standings = append(standings, Standing{player, i + 1})
+    }
+
+    return standings
 }
 
-func (s *StubPlayerStore) GetLeague() League {
+func (s *StubPlayerStore) GetLeagueIn(window Window) League {
     return s.league
 }
 
+func (s *StubPlayerStore) RecordGame(game GameRecord) (GameRecord, error) {
+    s.games = append(s.games, game)
+    return game, nil
+}
+
+// GetRatings rates the games recorded so far.
+func (s *StubPlayerStore) GetRatings() Ratings {
+    return RateGames(s.games)
+}
+
+// RecordResult records the winner's win and the game, or neither if the stub
+// fails to record wins.
+func (s *StubPlayerStore) RecordResult(game GameRecord) (GameRecord, error) {
+    if err := s.RecordWin(game.Winner()); err != nil {
+        return GameRecord{}, err
+    }
+    return s.RecordGame(game)
+}
+
+func (s *StubPlayerStore) RenamePlayer(from, to string) error {
+    if _, found := s.scores[from]; !found {
+        return fmt.Errorf("%w %q", ErrPlayerNotFound, from)
+    }
+    if _, found := s.scores[to]; found && from != to {
+        return fmt.Errorf("%w %q", ErrPlayerExists, to)
+    }
+    score := s.scores[from]
+    delete(s.scores, from)
+    s.scores[to] = score
+    return nil
+}
+
+func (s *StubPlayerStore) MergePlayers(from, into string) error {
+    if from == into {
+        return fmt.Errorf("%w, %q", ErrSamePlayer, from)
+    }
+    if _, found := s.scores[from]; !found {
+        return fmt.Errorf("%w %q", ErrPlayerNotFound, from)
+    }
+    s.scores[into] += s.scores[from]
+    delete(s.scores, from)
+    return nil
+}
+
+func (s *StubPlayerStore) DeletePlayer(name string) error {
+    if _, found := s.scores[name]; !found {
+        return fmt.Errorf("%w %q", ErrPlayerNotFound, name)
+    }
+    delete(s.scores, name)
+    return nil
+}
+
+func (s *StubPlayerStore) GetGames() []GameRecord {
+    return s.games
+}
+
+func (s *StubPlayerStore) GetGame(id string) (GameRecord, bool) {
+    for _, game := range s.games {
+        if game.ID == id {
+            return game, true
+        }
+    }
+    return GameRecord{}, false
+}
+
+// GameSpy records how it is used. Lock it to read its fields while a server
+// goroutine may be using it.
 type GameSpy struct {
+    sync.Mutex
+
     StartedWith  int
+    StartedWithPlayers []string
 	StartCalled bool
-    BlindAlert  []byte
+    StartedCtx  context.Context
+    StartedWithBlinds BlindStructure
+    BlindAlert  *BlindAlert
+    GameID      string
 
     FinishedCalled   bool
     FinishedWith string
+    FinishedPlacings []string
+    FinishErr        error
+
+    ClockCommands []ClockCommand
+    Clock         ClockState
+    ClockErr      error
 }
 
-func (g *GameSpy) Start(numberOfPlayers int, out io.Writer) {
-    g.StartedWith = numberOfPlayers
+func (g *GameSpy) Start(ctx context.Context, players []string, blinds BlindStructure, out io.Writer) {
+    g.Lock()
+    defer g.Unlock()
+
+    g.StartedWith = len(players)
+    g.StartedWithPlayers = players
 	g.StartCalled = true
-    out.Write(g.BlindAlert)
+    g.StartedCtx = ctx
+    g.StartedWithBlinds = blinds
+    if g.BlindAlert != nil {
+        writeBlindAlert(out, *g.BlindAlert)
+    }
 }
 
-func (g *GameSpy) Finish(winner string) {
+func (g *GameSpy) Finish(winner string, placings ...string) error {
+    g.Lock()
+    defer g.Unlock()
+
+    g.FinishedCalled = true
     g.FinishedWith = winner
+    g.FinishedPlacings = placings
+    return g.FinishErr
+}
+
+func (g *GameSpy) ControlClock(cmd ClockCommand) (ClockState, error) {
+    g.Lock()
+    defer g.Unlock()
+
+    g.ClockCommands = append(g.ClockCommands, cmd)
+    return g.Clock, g.ClockErr
+}
+
+func (g *GameSpy) ID() string {
+    g.Lock()
+    defer g.Unlock()
+
+    return g.GameID
+}
+
+func (g *GameSpy) ClockState() (ClockState, error) {
+    g.Lock()
+    defer g.Unlock()
+
+    return g.Clock, g.ClockErr
 }
 
 func AssertScheduledAlert(t testing.TB, got, want ScheduledAlert) {
@@ -0,0 +1,343 @@
+package poker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ProtocolVersion is the version of the messages sent over /ws. Messages
+// with any other version are rejected.
+const ProtocolVersion = 1
+
+type MessageType string
+
+const (
+    // StartMessage is sent by the client to start a game, see StartPayload.
+    StartMessage MessageType = "start"
+    // ResumeMessage is sent by the client instead of a StartMessage to pick
+    // up a game it lost its connection to, see ResumePayload.
+    ResumeMessage MessageType = "resume"
+    // SessionMessage is sent by the server when a game starts or is resumed,
+    // with the token to resume it with, see SessionPayload.
+    SessionMessage MessageType = "session"
+    // BlindMessage is sent by the server when the blinds go up, see BlindPayload.
+    BlindMessage MessageType = "blind"
+    // PauseMessage is sent by the client to pause or resume the blind clock,
+    // see PausePayload.
+    PauseMessage MessageType = "pause"
+    // ClockMessage is sent by the client to move the blind clock between
+    // levels, see ClockPayload.
+    ClockMessage MessageType = "clock"
+    // FinishMessage is sent by the client to declare the result, and to
+    // spectators once it has been, see FinishPayload.
+    FinishMessage MessageType = "finish"
+    // ErrorMessage is sent by the server when a message is rejected, see
+    // ErrorPayload.
+    ErrorMessage MessageType = "error"
+    // StateMessage is sent by the server when a game starts, in reply to
+    // clock messages and to spectators when the clock changes, see
+    // StatePayload.
+    StateMessage MessageType = "state"
+)
+
+var messageTypes = map[MessageType]bool{
+    StartMessage:   true,
+    ResumeMessage:  true,
+    SessionMessage: true,
+    BlindMessage:   true,
+    PauseMessage:   true,
+    ClockMessage:   true,
+    FinishMessage:  true,
+    ErrorMessage:   true,
+    StateMessage:   true,
+}
+
+var (
+    ErrBadMessage        = errors.New("bad message")
+    ErrUnexpectedMessage = errors.New("unexpected message")
+)
+
+// Message is the envelope every message over /ws is sent in, e.g.
+// {"version": 1, "type": "finish", "payload": {"winner": "Ruth"}}.
+type Message struct {
+    Version int             `json:"version"`
+    Type    MessageType     `json:"type"`
+    Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type StartPayload struct {
+    Players        []string `json:"players"`
+    BlindStructure string   `json:"blindStructure,omitempty"`
+}
+
+type ResumePayload struct {
+    Token string `json:"token"`
+}
+
+type SessionPayload struct {
+    GameID string `json:"gameId"`
+    Token  string `json:"token"`
+}
+
+// BlindPayload announces a blind level. Message is the alert as text, a
+// payload made from an alert also carries the level's blinds and how long it
+// lasts.
+type BlindPayload struct {
+    Message         string `json:"message"`
+    Level           int    `json:"level,omitempty"`
+    SmallBlind      int    `json:"smallBlind,omitempty"`
+    BigBlind        int    `json:"bigBlind,omitempty"`
+    Ante            int    `json:"ante,omitempty"`
+    DurationSeconds int    `json:"durationSeconds,omitempty"`
+}
+
+func newBlindPayload(alert BlindAlert) BlindPayload {
+    return BlindPayload{
+        Message:         alert.String(),
+        Level:           alert.Level,
+        SmallBlind:      alert.SmallBlind,
+        BigBlind:        alert.BigBlind,
+        Ante:            alert.Ante,
+        DurationSeconds: int(alert.Duration.Round(time.Second) / time.Second),
+    }
+}
+
+type PausePayload struct {
+    Paused bool `json:"paused"`
+}
+
+// ClockPayload carries one of the ClockCommands keywords, e.g. "advance".
+type ClockPayload struct {
+    Command string `json:"command"`
+}
+
+type FinishPayload struct {
+    Winner   string   `json:"winner"`
+    Placings []string `json:"placings,omitempty"`
+}
+
+type ErrorPayload struct {
+    Message string `json:"message"`
+}
+
+type StatePayload struct {
+    GameID           string `json:"gameId,omitempty"`
+    Level            int    `json:"level"`
+    SmallBlind       int    `json:"smallBlind"`
+    BigBlind         int    `json:"bigBlind"`
+    Ante             int    `json:"ante"`
+    RemainingSeconds int    `json:"remainingSeconds"`
+    Paused           bool   `json:"paused"`
+}
+
+func newStatePayload(gameID string, state ClockState) StatePayload {
+    return StatePayload{
+        GameID:           gameID,
+        Level:            state.Level,
+        SmallBlind:       state.SmallBlind,
+        BigBlind:         state.BigBlind,
+        Ante:             state.Ante,
+        RemainingSeconds: int(state.Remaining.Round(time.Second) / time.Second),
+        Paused:           state.Paused,
+    }
+}
+
+// MarshalJSON writes the state the way a StatePayload is written, so the
+// REST API and the WebSocket describe a clock the same way.
+func (s ClockState) MarshalJSON() ([]byte, error) {
+    return json.Marshal(newStatePayload("", s))
+}
+
+func (s *ClockState) UnmarshalJSON(data []byte) error {
+    var payload StatePayload
+
+    if err := json.Unmarshal(data, &payload); err != nil {
+        return err
+    }
+
+    *s = ClockState{
+        Level:      payload.Level,
+        SmallBlind: payload.SmallBlind,
+        BigBlind:   payload.BigBlind,
+        Ante:       payload.Ante,
+        Remaining:  time.Duration(payload.RemainingSeconds) * time.Second,
+        Paused:     payload.Paused,
+    }
+    return nil
+}
+
+// NewMessage wraps payload in a Message of the current version.
+func NewMessage(msgType MessageType, payload interface{}) (Message, error) {
+    msg := Message{Version: ProtocolVersion, Type: msgType}
+
+    if payload == nil {
+        return msg, nil
+    }
+
+    data, err := json.Marshal(payload)
+    if err != nil {
+        return msg, fmt.Errorf("problem encoding %s payload, %v", msgType, err)
+    }
+
+    msg.Payload = data
+    return msg, nil
+}
+
+// DecodeMessage reads a Message, checking its version and type. Any problem
+// with it is an ErrBadMessage.
+func DecodeMessage(data []byte) (Message, error) {
+    var msg Message
+
+    if err := json.Unmarshal(data, &msg); err != nil {
+        return msg, fmt.Errorf("%w, %v", ErrBadMessage, err)
+    }
+
+    if msg.Version != ProtocolVersion {
+        return msg, fmt.Errorf("%w, unsupported version %d, want %d", ErrBadMessage, msg.Version, ProtocolVersion)
+    }
+
+    if !messageTypes[msg.Type] {
+        return msg, fmt.Errorf("%w, unknown type %q", ErrBadMessage, msg.Type)
+    }
+
+    return msg, nil
+}
+
+// DecodePayload reads the message's payload into v, rejecting a missing
+// payload or fields v doesn't have.
+func (m Message) DecodePayload(v interface{}) error {
+    if len(m.Payload) == 0 {
+        return fmt.Errorf("%w, %s message has no payload", ErrBadMessage, m.Type)
+    }
+
+    dec := json.NewDecoder(bytes.NewReader(m.Payload))
+    dec.DisallowUnknownFields()
+
+    if err := dec.Decode(v); err != nil {
+        return fmt.Errorf("%w, problem reading %s payload, %v", ErrBadMessage, m.Type, err)
+    }
+
+    return nil
+}
+
+const (
+    // pongWait is how long a connection can go without answering a ping
+    // before it is treated as lost
+    pongWait   = 60 * time.Second
+    pingPeriod = pongWait * 9 / 10
+)
+
+// playerServerWS sends and receives Messages over a WebSocket. It is also
+// the BlindAlertWriter blind alerts go to, sending each one as a BlindMessage.
+type playerServerWS struct {
+    *websocket.Conn
+
+    // mu stops blind alerts and replies writing to the connection at once
+    mu sync.Mutex
+
+    closeOnce sync.Once
+    closed    chan struct{}
+}
+
+func newPlayerServerWS(w http.ResponseWriter, r *http.Request) (*playerServerWS, error) {
+    conn, err := wsUpgrader.Upgrade(w, r, nil)
+
+    if err != nil {
+        log.Printf("problem upgrading connection to WebSockets %v\n", err)
+        return nil, err
+    }
+
+    ws := &playerServerWS{Conn: conn, closed: make(chan struct{})}
+    ws.keepAlive()
+
+    return ws, nil
+}
+
+// keepAlive pings the client so that a connection that has silently gone
+// away fails to read rather than blocking forever.
+func (w *playerServerWS) keepAlive() {
+    w.SetReadDeadline(time.Now().Add(pongWait))
+    w.SetPongHandler(func(string) error {
+        return w.SetReadDeadline(time.Now().Add(pongWait))
+    })
+
+    go func() {
+        ticker := time.NewTicker(pingPeriod)
+        defer ticker.Stop()
+
+        for {
+            select {
+            case <-ticker.C:
+                if err := w.WriteControl(websocket.PingMessage, nil, time.Now().Add(pingPeriod)); err != nil {
+                    return
+                }
+            case <-w.closed:
+                return
+            }
+        }
+    }()
+}
+
+func (w *playerServerWS) Close() error {
+    w.closeOnce.Do(func() { close(w.closed) })
+    return w.Conn.Close()
+}
+
+// Receive waits for the next message. Messages that can't be decoded return
+// an ErrBadMessage, anything else is a problem with the connection.
+func (w *playerServerWS) Receive() (Message, error) {
+    _, data, err := w.ReadMessage()
+    if err != nil {
+        log.Printf("error reading from websocket %v\n", err)
+        return Message{}, err
+    }
+
+    return DecodeMessage(data)
+}
+
+func (w *playerServerWS) Send(msgType MessageType, payload interface{}) error {
+    msg, err := NewMessage(msgType, payload)
+    if err != nil {
+        return err
+    }
+
+    return w.SendMessage(msg)
+}
+
+func (w *playerServerWS) SendMessage(msg Message) error {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+
+    return w.WriteJSON(msg)
+}
+
+func (w *playerServerWS) SendError(err error) error {
+    return w.Send(ErrorMessage, ErrorPayload{Message: err.Error()})
+}
+
+func (w *playerServerWS) SendState(gameID string, state ClockState) error {
+    return w.Send(StateMessage, newStatePayload(gameID, state))
+}
+
+func (w *playerServerWS) WriteBlindAlert(alert BlindAlert) error {
+    return w.Send(BlindMessage, newBlindPayload(alert))
+}
+
+func (w *playerServerWS) Write(p []byte) (n int, err error) {
+    err = w.Send(BlindMessage, BlindPayload{Message: strings.TrimSpace(string(p))})
+
+    if err != nil {
+        return 0, err
+    }
+
+    return len(p), nil
+}
@@ -0,0 +1,67 @@
+package poker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMessages(t *testing.T) {
+    t.Run("payloads survive a round trip", func(t *testing.T) {
+        want := FinishPayload{Winner: "Ruth", Placings: []string{"Chris", "Cleo"}}
+
+        msg, err := NewMessage(FinishMessage, want)
+        assertNoError(t, err)
+
+        var got FinishPayload
+        assertNoError(t, msg.DecodePayload(&got))
+
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("got %+v, want %+v", got, want)
+        }
+    })
+
+    t.Run("decodes a message from a third party", func(t *testing.T) {
+        msg, err := DecodeMessage([]byte(`{"version": 1, "type": "clock", "payload": {"command": "next"}}`))
+        assertNoError(t, err)
+
+        var clock ClockPayload
+        assertNoError(t, msg.DecodePayload(&clock))
+
+        if msg.Type != ClockMessage || clock.Command != "next" {
+            t.Errorf("got %s %+v, want a clock message to go to the next level", msg.Type, clock)
+        }
+    })
+
+    cases := []struct {
+        name string
+        data string
+    }{
+        {"not JSON", `pause`},
+        {"no version", `{"type": "pause", "payload": {"paused": true}}`},
+        {"a newer version", `{"version": 2, "type": "pause", "payload": {"paused": true}}`},
+        {"an unknown type", `{"version": 1, "type": "deal"}`},
+    }
+
+    for _, c := range cases {
+        t.Run("rejects "+c.name, func(t *testing.T) {
+            _, err := DecodeMessage([]byte(c.data))
+
+            if !errors.Is(err, ErrBadMessage) {
+                t.Errorf("got error %v, want %v", err, ErrBadMessage)
+            }
+        })
+    }
+
+    t.Run("rejects payloads with fields it doesn't know", func(t *testing.T) {
+        msg, err := DecodeMessage([]byte(`{"version": 1, "type": "pause", "payload": {"pause": true}}`))
+        assertNoError(t, err)
+
+        var pause PausePayload
+        err = msg.DecodePayload(&pause)
+
+        if !errors.Is(err, ErrBadMessage) {
+            t.Errorf("got error %v, want %v", err, ErrBadMessage)
+        }
+    })
+}